@@ -0,0 +1,155 @@
+// Command decklint checks deck files and deck codes against the deck
+// construction rules.
+//
+// Files ending in .json are deck files as written by the deck package.
+// Any other file is a text deck list with one card per line as
+// "<count> <card name>"; the count may be omitted for a single copy. Blank
+// lines and lines starting with '#' are ignored.
+//
+//	decklint [-min 10] [-max 40] [-copies 3] [-factions a,b] [-banned x,y] deck.txt|deck.json...
+//	decklint -code <deck code>
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"GoGame/internal/card"
+	"GoGame/internal/deck"
+	"GoGame/internal/game"
+)
+
+func main() {
+	defaults := game.DefaultDeckRules()
+	minSize := flag.Int("min", defaults.MinSize, "minimum deck size")
+	maxSize := flag.Int("max", defaults.MaxSize, "maximum deck size, 0 for no limit")
+	copies := flag.Int("copies", defaults.MaxCopies, "maximum copies of one card, 0 for no limit")
+	factions := flag.String("factions", "", "comma-separated list of allowed factions")
+	banned := flag.String("banned", "", "comma-separated list of banned cards")
+	code := flag.String("code", "", "lint a deck code instead of deck files")
+	flag.Parse()
+
+	if flag.NArg() == 0 && *code == "" {
+		fmt.Fprintln(os.Stderr, "usage: decklint [flags] deck.txt|deck.json... | decklint [flags] -code <deck code>")
+		os.Exit(2)
+	}
+
+	rules := game.DeckRules{
+		MinSize:   *minSize,
+		MaxSize:   *maxSize,
+		MaxCopies: *copies,
+		Factions:  splitList(*factions),
+		Banned:    splitList(*banned),
+	}
+
+	failed := false
+	lint := func(label string, cards []card.Card, problems []string, err error) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", label, err)
+			failed = true
+			return
+		}
+		for _, v := range game.ValidateDeck(cards, rules) {
+			problems = append(problems, v.String())
+		}
+		if len(problems) == 0 {
+			fmt.Printf("%s: ok (%d cards)\n", label, len(cards))
+			return
+		}
+		failed = true
+		for _, p := range problems {
+			fmt.Printf("%s: %s\n", label, p)
+		}
+	}
+
+	if *code != "" {
+		cards, problems, err := buildList(deck.Decode(*code))
+		lint("deck code", cards, problems, err)
+	}
+	for _, path := range flag.Args() {
+		if filepath.Ext(path) == ".json" {
+			cards, problems, err := buildList(deck.ReadFile(path))
+			lint(path, cards, problems, err)
+			continue
+		}
+		cards, problems, err := readText(path)
+		lint(path, cards, problems, err)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// buildList resolves a deck file or code; unknown cards are problems.
+func buildList(list deck.List, err error) ([]card.Card, []string, error) {
+	if err != nil {
+		return nil, nil, err
+	}
+	cards, err := list.Build(game.FindCardByID)
+	if err != nil {
+		return nil, []string{err.Error()}, nil
+	}
+	return cards, nil, nil
+}
+
+// readText parses a text deck list. Lines that cannot be resolved are
+// returned as problems rather than errors, so every issue in a file is
+// reported at once.
+func readText(path string) ([]card.Card, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var cards []card.Card
+	var problems []string
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		count, name := 1, line
+		if fields := strings.SplitN(line, " ", 2); len(fields) == 2 {
+			if n, err := strconv.Atoi(fields[0]); err == nil {
+				count, name = n, strings.TrimSpace(fields[1])
+			}
+		}
+		if count < 1 {
+			problems = append(problems, fmt.Sprintf("line %d: invalid count %d", lineNo, count))
+			continue
+		}
+		if count > deck.MaxCards-len(cards) {
+			problems = append(problems, fmt.Sprintf("line %d: deck holds more than %d cards", lineNo, deck.MaxCards))
+			continue
+		}
+
+		c, ok := game.FindCard(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("line %d: unknown card %q", lineNo, name))
+			continue
+		}
+		for i := 0; i < count; i++ {
+			cards = append(cards, c)
+		}
+	}
+	return cards, problems, scanner.Err()
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,131 @@
+// Command deckopt evolves decks from the known cards with a genetic
+// algorithm. Each deck is scored by its win rate against a gauntlet: every
+// reference deck played by every listed opponent, from both seats. The best
+// decks are written as deck files. Runs with the same flags and seed give
+// the same decks.
+//
+//	deckopt -seed 1 -size 15 -pop 24 -gens 20 -ais random,greedy [-vs ref.json...] -out evolved -top 3
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"GoGame/internal/card"
+	"GoGame/internal/deck"
+	"GoGame/internal/evolve"
+	"GoGame/internal/game"
+	"GoGame/internal/sim"
+)
+
+func main() {
+	seed := flag.Int64("seed", 1, "random seed")
+	size := flag.Int("size", 15, "cards per evolved deck")
+	pop := flag.Int("pop", 24, "population size")
+	gens := flag.Int("gens", 20, "number of generations")
+	elite := flag.Int("elite", 2, "best decks kept unchanged each generation")
+	mutation := flag.Float64("mutation", 0.3, "chance that a new deck is mutated")
+	games := flag.Int("games", 4, "games per opponent and seat")
+	player := flag.String("player", "greedy", "controller playing the evolved decks: "+strings.Join(sim.ControllerNames(), ", "))
+	ais := flag.String("ais", "random,greedy", "comma-separated opponent controllers")
+	vs := flag.String("vs", "", "comma-separated reference deck files, default deck if empty")
+	workers := flag.Int("workers", 0, "parallel evaluations, 0 for one per CPU")
+	out := flag.String("out", "evolved", "directory for the best deck files")
+	top := flag.Int("top", 3, "number of decks to write")
+	flag.Parse()
+
+	playerFactory, err := sim.NamedController(*player)
+	if err != nil {
+		log.Fatal(err)
+	}
+	gauntlet, err := buildGauntlet(*ais, *vs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := evolve.Config{
+		Pool:        game.InitializeDeck(),
+		Rules:       sim.Rules(),
+		DeckSize:    *size,
+		Population:  *pop,
+		Generations: *gens,
+		Elite:       *elite,
+		Mutation:    *mutation,
+		Games:       *games,
+		Gauntlet:    gauntlet,
+		Player:      playerFactory,
+		Seed:        *seed,
+		Workers:     *workers,
+		Progress: func(gen int, best evolve.Individual) {
+			fmt.Fprintf(os.Stderr, "generation %d: best win rate %.1f%%\n", gen+1, best.Fitness*100)
+		},
+	}
+	population, err := evolve.Run(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+	for i := 0; i < *top && i < len(population); i++ {
+		ind := population[i]
+		name := fmt.Sprintf("Evolved #%d (seed %d, %.0f%%)", i+1, *seed, ind.Fitness*100)
+		path := filepath.Join(*out, fmt.Sprintf("deck_%d.json", i+1))
+		list, err := deck.FromCards(name, ind.Cards(cfg.Pool))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := deck.WriteFile(path, list); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: %.1f%% %s\n", path, ind.Fitness*100, describe(ind, cfg.Pool))
+	}
+}
+
+// buildGauntlet pairs every reference deck with every opponent controller.
+func buildGauntlet(ais, vs string) ([]evolve.Opponent, error) {
+	decks := map[string][]card.Card{"default": game.InitializeDeck()}
+	order := []string{"default"}
+	if vs != "" {
+		decks, order = map[string][]card.Card{}, nil
+		for _, path := range strings.Split(vs, ",") {
+			list, err := deck.ReadFile(strings.TrimSpace(path))
+			if err != nil {
+				return nil, err
+			}
+			cards, err := list.Build(game.FindCardByID)
+			if err != nil {
+				return nil, err
+			}
+			decks[path], order = cards, append(order, path)
+		}
+	}
+
+	var gauntlet []evolve.Opponent
+	for _, name := range strings.Split(ais, ",") {
+		name = strings.TrimSpace(name)
+		factory, err := sim.NamedController(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range order {
+			gauntlet = append(gauntlet, evolve.Opponent{Name: name + "/" + d, Deck: decks[d], Controller: factory})
+		}
+	}
+	return gauntlet, nil
+}
+
+func describe(ind evolve.Individual, pool []card.Card) string {
+	var parts []string
+	for i, n := range ind.Counts {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%dx %s", n, pool[i].Name))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
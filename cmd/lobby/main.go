@@ -0,0 +1,58 @@
+// Command lobby runs a matchmaking lobby: players list and create games,
+// join them by code or ask for a quick match, and play on the netplay
+// server built into the lobby. The protocol is described in package lobby.
+//
+//	lobby -addr :7778 [-seed 1] [-ai normal] [-quick-wait 10s] [-ready 30s] [-grace 30s] [-turn-time 1m] [-verify]
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"GoGame/internal/ai"
+	"GoGame/internal/game"
+	"GoGame/internal/lobby"
+	"GoGame/internal/netplay"
+)
+
+func main() {
+	addr := flag.String("addr", ":7778", "address to listen on")
+	seed := flag.Int64("seed", 0, "seed of the first game; game n uses seed+n, 0 seeds from the clock")
+	difficulty := flag.String("ai", ai.Normal.String(), "difficulty of the quick match fallback opponent, or \"none\"")
+	quickWait := flag.Duration("quick-wait", 10*time.Second, "how long a quick match waits for a human opponent")
+	ready := flag.Duration("ready", 30*time.Second, "time to confirm being ready")
+	grace := flag.Duration("grace", netplay.DefaultGrace, "how long a disconnected player's seat is held")
+	turnTime := flag.Duration("turn-time", 0, "time limit per turn, 0 for none")
+	verify := flag.Bool("verify", false, "let the players agree on the shuffle seed and check it after the game")
+	flag.Parse()
+
+	server := netplay.NewServer()
+	server.Seed = *seed
+	server.Grace = *grace
+	server.TurnTime = *turnTime
+	server.Verifiable = *verify
+	server.Log = log.New(os.Stderr, "", log.LstdFlags)
+
+	s := lobby.NewService(server, lobby.NewMemoryStore())
+	s.QuickWait = *quickWait
+	s.ReadyTime = *ready
+	if *difficulty != "none" {
+		d, ok := ai.ParseDifficulty(*difficulty)
+		if !ok {
+			log.Fatalf("unknown difficulty %q", *difficulty)
+		}
+		s.AI = func() game.Controller {
+			return ai.NewController(d, ai.DefaultWeights(), time.Now().UnixNano())
+		}
+	}
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server.Log.Printf("lobby listening on %s", l.Addr())
+	log.Fatal(s.Serve(l))
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"flag"
+	"log"
 	"math/rand"
+	"os"
+	"strings"
 	"time"
 
+	"GoGame/internal/ai"
+	"GoGame/internal/bot"
 	"GoGame/internal/game"
 	"GoGame/internal/ui"
 
@@ -11,17 +17,39 @@ import (
 )
 
 func main() {
+	weightsFile := flag.String("ai-weights", "", "JSON file with evaluation weights for the computer player")
+	botCommand := flag.String("bot", "", "command line of an external bot program to play the computer seat")
+	flag.Parse()
+
 	rand.Seed(time.Now().UnixNano())
 
-	a := app.New()
+	weights := ai.DefaultWeights()
+	if *weightsFile != "" {
+		var err error
+		if weights, err = ai.LoadWeights(*weightsFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	a := app.NewWithID("io.github.ll1yt.gogame")
 	w := a.NewWindow("Go Card Game")
 
-	g := game.NewGame()
+	g, err := game.NewGame()
+	if err != nil {
+		log.Fatal(err)
+	}
 	g.SetWindow(w)
 
 	// Initialize the deck and deal initial hands
 	g.DealInitialHands()
 
+	ui.SetAIWeights(weights)
+	if args := strings.Fields(*botCommand); len(args) > 0 {
+		opponent := bot.New(args[0], args[1:]...)
+		opponent.Log = os.Stderr
+		defer opponent.Close()
+		ui.SetBot(opponent)
+	}
 	ui.SetupUI(g)
 
 	// Start the game loop in a separate goroutine
@@ -0,0 +1,53 @@
+// Command refbot is the reference bot for the bot protocol. It plays its
+// most powerful card and redraws the weak units of its opening hand.
+// Bots in other languages can start from its behaviour.
+//
+//	sim -p1 "bot:refbot -name Ref" -p2 greedy
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"GoGame/internal/bot"
+	"GoGame/internal/card"
+	"GoGame/internal/game"
+)
+
+// mulliganPower is the power up to which an opening unit is redrawn.
+const mulliganPower = 2
+
+type refbot struct{}
+
+func (refbot) Mulligan(v game.View) []int {
+	var indices []int
+	for i, c := range v.Self.Hand {
+		if c.Type == card.UnitCard && c.Power <= mulliganPower {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+func (refbot) NextAction(v game.View) game.Action {
+	best := game.Action{Kind: game.ActionEndTurn}
+	bestPower := -1
+	for _, a := range v.LegalActions() {
+		if a.Kind != game.ActionPlayCard {
+			continue
+		}
+		if power := v.Self.Hand[a.CardIndex].Power; power > bestPower {
+			best, bestPower = a, power
+		}
+	}
+	return best
+}
+
+func main() {
+	name := flag.String("name", "Reference Bot", "name sent in the id line")
+	flag.Parse()
+	if err := bot.Run(os.Stdin, os.Stdout, *name, refbot{}); err != nil {
+		log.Fatal(err)
+	}
+}
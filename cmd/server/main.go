@@ -0,0 +1,66 @@
+// Command server hosts games over TCP. Every two clients that connect play a
+// game against each other, with the server as the only authority. The
+// JSON-lines protocol is described in package netplay.
+//
+//	server -addr :7777 [-seed 1] [-legacy] [-turns 20] [-grace 30s] [-turn-time 1m] [-announce "Alice's game"] [-verify]
+//
+// With -announce the server is listed in the "Join LAN game" dialog of
+// players on the local network. With -verify the players agree on the
+// shuffle seed and can check every draw after the game, so they need not
+// trust whoever runs the server.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"GoGame/internal/discovery"
+	"GoGame/internal/game"
+	"GoGame/internal/netplay"
+)
+
+func main() {
+	addr := flag.String("addr", ":7777", "address to listen on")
+	seed := flag.Int64("seed", 0, "seed of the first game; game n uses seed+n, 0 seeds from the clock")
+	legacy := flag.Bool("legacy", false, "use the legacy rules")
+	turns := flag.Int("turns", -1, "turn limit, 0 for none, -1 for the ruleset's default")
+	grace := flag.Duration("grace", netplay.DefaultGrace, "how long a disconnected player's seat is held")
+	turnTime := flag.Duration("turn-time", 0, "time limit per turn, 0 for none")
+	announce := flag.String("announce", "", "announce the server on the local network under this name")
+	verify := flag.Bool("verify", false, "let the players agree on the shuffle seed and check it after the game")
+	flag.Parse()
+
+	s := netplay.NewServer()
+	ruleset := "default"
+	if *legacy {
+		s.Rules = game.LegacyRules()
+		ruleset = "legacy"
+	}
+	if *turns >= 0 {
+		s.Rules.TurnLimit = *turns
+	}
+	s.Seed = *seed
+	s.Grace = *grace
+	s.TurnTime = *turnTime
+	s.Verifiable = *verify
+	s.Log = log.New(os.Stderr, "", log.LstdFlags)
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.Log.Printf("listening on %s", l.Addr())
+	if *announce != "" {
+		port := l.Addr().(*net.TCPAddr).Port
+		beacon := &discovery.Beacon{Interval: time.Second, Info: func() discovery.Announcement {
+			return discovery.Announcement{Name: *announce, Ruleset: ruleset, Seats: s.OpenSeats(), Port: port}
+		}}
+		if err := beacon.Start(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	log.Fatal(s.Serve(l))
+}
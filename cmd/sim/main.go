@@ -0,0 +1,125 @@
+// Command sim plays games between computer controllers without a window and
+// reports win rates, game lengths, how games ended and how often each card
+// was played. It can also export per-card balance statistics, either from the
+// games it just played or from previously recorded games.
+//
+//	sim -n 1000 -p1 greedy -p2 random -seed 1 [-json] [-legacy] [-record games.jsonl]
+//	sim -load games.jsonl -cards cards.csv -threshold 0.1 -min-games 30
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"GoGame/internal/analytics"
+	"GoGame/internal/game"
+	"GoGame/internal/sim"
+)
+
+func main() {
+	n := flag.Int("n", 100, "number of games")
+	p1 := flag.String("p1", "greedy", "controller for seat 1: "+strings.Join(sim.ControllerNames(), ", "))
+	p2 := flag.String("p2", "random", "controller for seat 2")
+	seed := flag.Int64("seed", 1, "seed of the first game; game i uses seed+i")
+	workers := flag.Int("workers", 0, "parallel games, 0 for one per CPU")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	legacy := flag.Bool("legacy", false, "use the legacy rules")
+	recordFile := flag.String("record", "", "write the played games to this JSON lines file")
+	loadFile := flag.String("load", "", "analyse games from this JSON lines file instead of playing")
+	cardsFile := flag.String("cards", "", "write per-card statistics to this CSV file")
+	threshold := flag.Float64("threshold", 0.1, "flag cards whose win rate delta exceeds this")
+	minGames := flag.Int("min-games", analytics.DefaultMinGames, "only flag cards played in at least this many games")
+	flag.Parse()
+
+	var records []sim.GameRecord
+	var err error
+	if *loadFile != "" {
+		records, err = loadRecords(*loadFile)
+	} else {
+		records, err = play(*p1, *p2, *n, *seed, *workers, *legacy)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *recordFile != "" {
+		if err := writeFile(*recordFile, func(f *os.File) error { return sim.WriteRecords(f, records) }); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *cardsFile != "" {
+		if err := writeCardStats(*cardsFile, records, *threshold, *minGames); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	seats := [2]string{*p1, *p2}
+	if *loadFile != "" && len(records) > 0 {
+		seats = records[0].Seats
+	}
+	report := sim.Summarize(records, seats)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	report.WriteText(os.Stdout)
+	fmt.Println()
+}
+
+func play(p1, p2 string, n int, seed int64, workers int, legacy bool) ([]sim.GameRecord, error) {
+	c1, err := sim.NamedController(p1)
+	if err != nil {
+		return nil, err
+	}
+	c2, err := sim.NamedController(p2)
+	if err != nil {
+		return nil, err
+	}
+
+	match := sim.DefaultMatch(c1, c2)
+	if legacy {
+		match.Rules = game.LegacyRules()
+	}
+	return sim.RunMany(match, n, seed, workers)
+}
+
+func loadRecords(path string) ([]sim.GameRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return sim.ReadRecords(f)
+}
+
+func writeCardStats(path string, records []sim.GameRecord, threshold float64, minGames int) error {
+	games := make([]analytics.Game, len(records))
+	for i, rec := range records {
+		games[i] = rec.AnalyticsGame()
+	}
+	report := analytics.Analyze(games, threshold, minGames)
+	for _, name := range report.FlaggedCards {
+		fmt.Fprintf(os.Stderr, "flagged: %s\n", name)
+	}
+	return writeFile(path, func(f *os.File) error { return report.WriteCSV(f) })
+}
+
+func writeFile(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"GoGame/internal/game"
+)
+
+// Difficulty selects the strength of a computer opponent.
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Normal
+	Hard
+	Expert
+)
+
+// Difficulties lists every level from weakest to strongest.
+var Difficulties = []Difficulty{Easy, Normal, Hard, Expert}
+
+func (d Difficulty) String() string {
+	switch d {
+	case Easy:
+		return "Easy"
+	case Normal:
+		return "Normal"
+	case Hard:
+		return "Hard"
+	case Expert:
+		return "Expert"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParseDifficulty accepts a level name in any letter case.
+func ParseDifficulty(s string) (Difficulty, bool) {
+	for _, d := range Difficulties {
+		if strings.EqualFold(d.String(), s) {
+			return d, true
+		}
+	}
+	return Normal, false
+}
+
+// MistakeRate is the chance that the opponent replaces its chosen action
+// with a random legal one.
+func (d Difficulty) MistakeRate() float64 {
+	switch d {
+	case Easy:
+		return 0.4
+	case Normal:
+		return 0.15
+	case Hard:
+		return 0.05
+	default:
+		return 0
+	}
+}
+
+// NewController creates the opponent for a difficulty level: greedy play
+// at Easy and Normal, MCTS with a growing budget at Hard and Expert.
+func NewController(d Difficulty, w Weights, seed int64) game.Controller {
+	var c game.Controller
+	switch d {
+	case Easy, Normal:
+		c = NewGreedy(w)
+	case Hard:
+		c = NewMCTS(MCTSConfig{Iterations: 300, Seed: seed, Weights: w})
+	default:
+		c = NewMCTS(MCTSConfig{TimeLimit: 1500 * time.Millisecond, Seed: seed, Weights: w})
+	}
+	if rate := d.MistakeRate(); rate > 0 {
+		c = WithMistakes(c, rate, rand.New(rand.NewSource(seed)))
+	}
+	return c
+}
+
+// WithMistakes wraps a controller so that with probability rate a random
+// legal action is played instead of the controller's own.
+func WithMistakes(c game.Controller, rate float64, rng *rand.Rand) game.Controller {
+	return &mistakes{Controller: c, rate: rate, rng: rng}
+}
+
+type mistakes struct {
+	game.Controller
+	rate float64
+	rng  *rand.Rand
+}
+
+func (m *mistakes) Reset() {
+	if r, ok := m.Controller.(interface{ Reset() }); ok {
+		r.Reset()
+	}
+}
+
+// NextAction decides on a mistake before asking the wrapped controller, so
+// a controller that keeps state between actions only sees the actions it
+// chose itself.
+func (m *mistakes) NextAction(v game.View) game.Action {
+	if legal := v.LegalActions(); len(legal) > 0 && m.rng.Float64() < m.rate {
+		return legal[m.rng.Intn(len(legal))]
+	}
+	return m.Controller.NextAction(v)
+}
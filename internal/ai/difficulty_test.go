@@ -0,0 +1,42 @@
+package ai
+
+import (
+	"math/rand"
+	"testing"
+
+	"GoGame/internal/game"
+)
+
+func TestPlayGoesOnAfterAMistake(t *testing.T) {
+	for seed := int64(1); seed < 100; seed++ {
+		g := newGame(t, "Soldier", "Fireball", "Heal", "Shield")
+		m := WithMistakes(NewGreedy(DefaultWeights()), 1, rand.New(rand.NewSource(seed))).(*mistakes)
+		mistake := m.NextAction(g.ViewFor(&g.Player1))
+		// the soldier is not part of the greedy plan
+		if mistake.Kind != game.ActionPlayCard || mistake.CardIndex != 0 {
+			continue
+		}
+		if err := g.Apply(&g.Player1, mistake); err != nil {
+			t.Fatal(err)
+		}
+
+		m.rate = 0
+		plays := 0
+		for a := m.NextAction(g.ViewFor(&g.Player1)); a.Kind != game.ActionEndTurn; a = m.NextAction(g.ViewFor(&g.Player1)) {
+			if err := g.Apply(&g.Player1, a); err != nil {
+				t.Fatalf("play %d after the mistake: %v", plays+1, err)
+			}
+			plays++
+		}
+		if plays == 0 {
+			t.Fatal("no plays after the mistake")
+		}
+		for _, c := range g.Player1.Hand {
+			if c.Name == "Fireball" {
+				t.Fatalf("hand %v at the end of the turn, want the fireball played", g.Player1.Hand)
+			}
+		}
+		return
+	}
+	t.Fatal("no seed made the mistake of playing the soldier")
+}
@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"GoGame/internal/card"
+	"GoGame/internal/game"
+)
+
+// maxPlanCards bounds how many cards of the hand the planner combines.
+const maxPlanCards = 10
+
+// Greedy plans its whole turn at the start: it tries every combination of
+// cards it can afford, scores the resulting position with Evaluate and plays
+// the best one. It plans again when the hand is not the one the rest of
+// its plan expects, for example after a wrapper played something else.
+type Greedy struct {
+	Weights Weights
+
+	plan     []game.Action
+	planTurn int
+	hand     []card.Card // the hand the rest of the plan expects
+}
+
+// NewGreedy creates a greedy player with the given personality.
+func NewGreedy(w Weights) *Greedy {
+	return &Greedy{Weights: w, planTurn: -1}
+}
+
+// Reset drops the plan of the previous game, whose turn numbers the next
+// game reuses.
+func (g *Greedy) Reset() {
+	g.plan, g.planTurn, g.hand = nil, -1, nil
+}
+
+func (g *Greedy) Mulligan(v game.View) []int {
+	return game.AIMulligan(v)
+}
+
+func (g *Greedy) NextAction(v game.View) game.Action {
+	if g.planTurn != v.Turn || !sameCards(g.hand, v.Self.Hand) {
+		g.planTurn = v.Turn
+		g.plan = BestSequence(v, g.Weights)
+	}
+	if len(g.plan) == 0 {
+		return game.Action{Kind: game.ActionEndTurn}
+	}
+	next := g.plan[0]
+	g.plan = g.plan[1:]
+	g.hand = append(append([]card.Card(nil), v.Self.Hand[:next.CardIndex]...), v.Self.Hand[next.CardIndex+1:]...)
+	return next
+}
+
+func sameCards(a, b []card.Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Same(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// BestSequence returns the highest scoring set of plays for this turn. Cards
+// are played from the highest hand position down, so the positions of the
+// cards still to come do not shift.
+func BestSequence(v game.View, w Weights) []game.Action {
+	best := Evaluate(v, w)
+	var bestPlan []game.Action
+
+	var search func(v game.View, below int, plan []game.Action)
+	search = func(v game.View, below int, plan []game.Action) {
+		for i := below - 1; i >= 0; i-- {
+			action := game.Action{Kind: game.ActionPlayCard, CardIndex: i}
+			next, err := v.Simulate(action)
+			if err != nil {
+				continue
+			}
+			extended := append(append([]game.Action(nil), plan...), action)
+			if score := Evaluate(next, w); score > best {
+				best, bestPlan = score, extended
+			}
+			search(next, i, extended)
+		}
+	}
+
+	hand := len(v.Self.Hand)
+	if hand > maxPlanCards {
+		hand = maxPlanCards
+	}
+	search(v, hand, nil)
+	return bestPlan
+}
@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"io"
+	"testing"
+
+	"GoGame/internal/game"
+)
+
+// newGame returns a game on player 1's play phase with the named cards as
+// player 1's whole hand.
+func newGame(t *testing.T, hand ...string) *game.Game {
+	t.Helper()
+	g, err := game.NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.Seed(1)
+	g.DealInitialHands()
+	g.StartTurn()
+	g.Player1.Hand = nil
+	for _, name := range hand {
+		c, ok := game.FindCard(name)
+		if !ok {
+			t.Fatalf("no card %q", name)
+		}
+		g.Player1.Hand = append(g.Player1.Hand, c)
+	}
+	g.Rehash()
+	return g
+}
+
+func names(v game.View, plan []game.Action) []string {
+	var out []string
+	for _, a := range plan {
+		out = append(out, v.Self.Hand[a.CardIndex].Name)
+	}
+	return out
+}
+
+func TestEvaluateFollowsWeights(t *testing.T) {
+	g := newGame(t, "Soldier")
+	v := g.ViewFor(&g.Player1)
+	base := Evaluate(v, DefaultWeights())
+
+	g.Player2.Health -= 5
+	if got := Evaluate(g.ViewFor(&g.Player1), DefaultWeights()); got <= base {
+		t.Fatalf("opponent losing health scored %v, was %v", got, base)
+	}
+	if got := Evaluate(g.ViewFor(&g.Player1), Weights{}); got != 0 {
+		t.Fatalf("zero weights scored %v", got)
+	}
+}
+
+func TestEachWeightChangesEvaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		w      Weights
+		change func(g *game.Game)
+		want   float64
+	}{
+		{"health", Weights{Health: 1}, func(g *game.Game) { g.Player2.Health -= 4 }, 4},
+		{"armor", Weights{Armor: 1}, func(g *game.Game) { g.Player1.AddArmor(2) }, 2},
+		{"mana efficiency", Weights{ManaEfficiency: 1}, func(g *game.Game) { g.Player1.Mana -= 5 }, 0.5},
+		{"card advantage", Weights{CardAdvantage: 1}, func(g *game.Game) { g.Player2.Hand = g.Player2.Hand[1:] }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame(t, "Soldier")
+			before := Evaluate(g.ViewFor(&g.Player1), tt.w)
+			tt.change(g)
+			if got := Evaluate(g.ViewFor(&g.Player1), tt.w) - before; got != tt.want {
+				t.Fatalf("score changed by %v, want %v", got, tt.want)
+			}
+			if got := Evaluate(g.ViewFor(&g.Player1), Weights{}); got != 0 {
+				t.Fatalf("zero weights scored %v", got)
+			}
+		})
+	}
+}
+
+func TestBestSequencePlaysUsefulCards(t *testing.T) {
+	g := newGame(t, "Soldier", "Fireball", "Heal", "Shield")
+	v := g.ViewFor(&g.Player1)
+
+	// at full health Heal only spends mana, and the Soldier is not worth a
+	// card
+	got := names(v, BestSequence(v, DefaultWeights()))
+	if len(got) != 3 || got[0] != "Shield" || got[1] != "Heal" || got[2] != "Fireball" {
+		t.Fatalf("plan %v, want Shield, Heal and Fireball", got)
+	}
+
+	// with card advantage worth more than anything else it keeps its hand
+	if plan := BestSequence(v, Weights{Health: 1, CardAdvantage: 10}); len(plan) != 0 {
+		t.Fatalf("plan %v, want no plays", names(v, plan))
+	}
+}
+
+func TestGreedyResetDropsOldPlan(t *testing.T) {
+	greedy := NewGreedy(DefaultWeights())
+	g := newGame(t, "Fireball", "Shield")
+	if a := greedy.NextAction(g.ViewFor(&g.Player1)); a.Kind != game.ActionPlayCard {
+		t.Fatalf("first action %v", a)
+	}
+
+	// a new game on the same turn number, with nothing worth playing
+	greedy.Reset()
+	next := newGame(t, "Soldier")
+	if a := greedy.NextAction(next.ViewFor(&next.Player1)); a.Kind != game.ActionEndTurn {
+		t.Fatalf("after Reset %v, want the turn ended", a)
+	}
+}
@@ -0,0 +1,197 @@
+package ai
+
+import (
+	"io"
+	"math"
+	"math/rand"
+	"time"
+
+	"GoGame/internal/card"
+	"GoGame/internal/game"
+)
+
+// MCTSConfig sets the search budget and behaviour of an MCTS player.
+// With only an iteration budget the search is fully reproducible from Seed.
+type MCTSConfig struct {
+	Iterations   int           // playouts per decision, 0 for no limit
+	TimeLimit    time.Duration // thinking time per decision, 0 for no limit
+	Seed         int64
+	Exploration  float64     // UCT exploration constant, 0 for sqrt(2)
+	RolloutTurns int         // turns played out after the tree, 0 for 6
+	Pool         []card.Card // the opponent's assumed deck list, nil for InitializeDeck
+	Weights      Weights     // scores unfinished playouts, zero for DefaultWeights
+}
+
+// defaultIterations is used when neither budget is set.
+const defaultIterations = 1000
+
+// MCTS searches the current turn with Monte Carlo Tree Search. Each playout
+// runs on a fresh sample of the determinized game: its own deck is shuffled
+// and the opponent's hand and deck are dealt from the unseen cards of the
+// assumed deck list, so the search never peeks at hidden cards.
+type MCTS struct {
+	cfg MCTSConfig
+	rng *rand.Rand
+}
+
+// NewMCTS creates an MCTS player.
+func NewMCTS(cfg MCTSConfig) *MCTS {
+	if cfg.Iterations == 0 && cfg.TimeLimit == 0 {
+		cfg.Iterations = defaultIterations
+	}
+	if cfg.Exploration == 0 {
+		cfg.Exploration = math.Sqrt2
+	}
+	if cfg.RolloutTurns == 0 {
+		cfg.RolloutTurns = 6
+	}
+	if cfg.Pool == nil {
+		cfg.Pool = game.InitializeDeck()
+	}
+	if cfg.Weights == (Weights{}) {
+		cfg.Weights = DefaultWeights()
+	}
+	return &MCTS{cfg: cfg, rng: rand.New(rand.NewSource(cfg.Seed))}
+}
+
+func (m *MCTS) Mulligan(v game.View) []int {
+	return game.AIMulligan(v)
+}
+
+func (m *MCTS) NextAction(v game.View) game.Action {
+	legal := v.LegalActions()
+	if len(legal) <= 1 {
+		return game.Action{Kind: game.ActionEndTurn}
+	}
+
+	root := &node{untried: legal}
+	d := v.Determinize(m.cfg.Pool)
+	start := time.Now()
+	for i := 0; m.withinBudget(i, start); i++ {
+		m.iterate(root, d)
+	}
+
+	var best *node
+	for _, child := range root.children {
+		if best == nil || child.visits > best.visits {
+			best = child
+		}
+	}
+	if best == nil {
+		return game.Action{Kind: game.ActionEndTurn}
+	}
+	return best.action
+}
+
+func (m *MCTS) withinBudget(i int, start time.Time) bool {
+	if m.cfg.Iterations > 0 && i >= m.cfg.Iterations {
+		return false
+	}
+	if m.cfg.TimeLimit > 0 && time.Since(start) >= m.cfg.TimeLimit {
+		return false
+	}
+	return true
+}
+
+// node is one decision of the searching player within the current turn.
+type node struct {
+	action   game.Action
+	parent   *node
+	children []*node
+	untried  []game.Action
+	visits   int
+	value    float64
+}
+
+func (n *node) selectChild(c float64) *node {
+	var best *node
+	bestScore := math.Inf(-1)
+	for _, child := range n.children {
+		score := child.value/float64(child.visits) +
+			c*math.Sqrt(math.Log(float64(n.visits))/float64(child.visits))
+		if score > bestScore {
+			best, bestScore = child, score
+		}
+	}
+	return best
+}
+
+func (m *MCTS) iterate(root *node, d *game.Determinization) {
+	g := d.Sample(m.rng)
+	g.Output = io.Discard
+	g.Rand = m.rng
+	me := &g.Player1
+
+	// Selection
+	n := root
+	for len(n.untried) == 0 && len(n.children) > 0 {
+		n = n.selectChild(m.cfg.Exploration)
+		g.Apply(me, n.action)
+	}
+
+	// Expansion
+	if len(n.untried) > 0 {
+		i := m.rng.Intn(len(n.untried))
+		action := n.untried[i]
+		n.untried = append(n.untried[:i], n.untried[i+1:]...)
+		g.Apply(me, action)
+
+		child := &node{action: action, parent: n}
+		if action.Kind != game.ActionEndTurn && !g.CheckGameOver() {
+			child.untried = g.ViewFor(me).LegalActions()
+		}
+		n.children = append(n.children, child)
+		n = child
+	}
+
+	// Playout
+	value := m.rollout(g, n.action.Kind == game.ActionEndTurn)
+
+	// Backpropagation
+	for ; n != nil; n = n.parent {
+		n.visits++
+		n.value += value
+	}
+}
+
+// rollout plays the game on with random moves and returns the result for
+// Player1: 1 for a win, 0 for a loss, and an evaluation in between when the
+// game does not finish within RolloutTurns.
+func (m *MCTS) rollout(g *game.Game, turnEnded bool) float64 {
+	if !turnEnded {
+		m.randomPlays(g)
+	}
+	for t := 0; t < m.cfg.RolloutTurns && !g.CheckGameOver(); t++ {
+		g.SwitchTurn()
+		g.StartTurn()
+		m.randomPlays(g)
+	}
+
+	if g.CheckGameOver() {
+		switch g.Result().Winner {
+		case &g.Player1:
+			return 1
+		case nil:
+			return 0.5
+		default:
+			return 0
+		}
+	}
+	score := Evaluate(g.ViewFor(&g.Player1), m.cfg.Weights)
+	return 1 / (1 + math.Exp(-score/10))
+}
+
+func (m *MCTS) randomPlays(g *game.Game) {
+	p := g.CurrentPlayer
+	for !g.CheckGameOver() {
+		legal := g.ViewFor(p).LegalActions()
+		if len(legal) == 0 {
+			return
+		}
+		i := m.rng.Intn(len(legal))
+		if legal[i].Kind == game.ActionEndTurn {
+			return
+		}
+		g.Apply(p, legal[i])
+	}
+}
@@ -0,0 +1,32 @@
+package ai
+
+import (
+	"testing"
+
+	"GoGame/internal/game"
+)
+
+func TestMCTSFindsTheWinningPlay(t *testing.T) {
+	g := newGame(t, "Soldier", "Heal", "Fireball", "Archer")
+	g.Player2.Health = 3
+	v := g.ViewFor(&g.Player1)
+
+	m := NewMCTS(MCTSConfig{Iterations: 200, Seed: 1})
+	a := m.NextAction(v)
+	if a.Kind != game.ActionPlayCard || v.Self.Hand[a.CardIndex].Name != "Fireball" {
+		t.Fatalf("chose %v, want the fireball that wins", a)
+	}
+}
+
+func TestMCTSIsReproducible(t *testing.T) {
+	g := newGame(t, "Soldier", "Heal", "Fireball", "Shield", "Knight")
+	v := g.ViewFor(&g.Player1)
+
+	for seed := int64(1); seed <= 3; seed++ {
+		a := NewMCTS(MCTSConfig{Iterations: 100, Seed: seed}).NextAction(v)
+		b := NewMCTS(MCTSConfig{Iterations: 100, Seed: seed}).NextAction(v)
+		if a != b {
+			t.Fatalf("seed %d: %v then %v", seed, a, b)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Package ai contains computer opponents that plug into game.Controller.
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"GoGame/internal/game"
+)
+
+// Weights tune the board evaluation. They can be loaded from a JSON file,
+// so new personalities need no code changes.
+type Weights struct {
+	Health         float64 `json:"health"`
+	Armor          float64 `json:"armor"`
+	ManaEfficiency float64 `json:"mana_efficiency"` // share of this turn's mana spent
+	CardAdvantage  float64 `json:"card_advantage"`
+}
+
+// DefaultWeights returns a balanced personality.
+func DefaultWeights() Weights {
+	return Weights{
+		Health:         1.0,
+		Armor:          0.8,
+		ManaEfficiency: 2.0,
+		CardAdvantage:  0.3,
+	}
+}
+
+// LoadWeights reads weights from a JSON file. Fields missing from the file
+// keep their default values.
+func LoadWeights(path string) (Weights, error) {
+	w := DefaultWeights()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return w, err
+	}
+	if err := json.Unmarshal(data, &w); err != nil {
+		return w, fmt.Errorf("load weights %s: %w", path, err)
+	}
+	return w, nil
+}
+
+// Evaluate scores a position from the viewing player's side; higher is better.
+func Evaluate(v game.View, w Weights) float64 {
+	self, opp := v.Self, v.Opponent
+
+	score := w.Health * float64(self.Health-opp.Health)
+	score += w.Armor * float64(self.Armor-opp.Armor)
+	if self.MaxMana > 0 && v.MyTurn {
+		spent := self.MaxMana - self.Mana
+		score += w.ManaEfficiency * float64(spent) / float64(self.MaxMana)
+	}
+	score += w.CardAdvantage * float64(self.HandSize+self.DeckSize-opp.HandSize-opp.DeckSize)
+	return score
+}
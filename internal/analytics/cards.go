@@ -0,0 +1,179 @@
+// Package analytics computes card balance statistics from finished games.
+package analytics
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"GoGame/internal/game"
+)
+
+// Game is one finished game as seen by the analytics: the names of both
+// players, the winner's name (empty for a tie) and the event record.
+type Game struct {
+	Players [2]string
+	Winner  string
+	Events  []game.Event
+}
+
+// CardStats holds the statistics of one card across many games. A "game"
+// here is one player's side of a game, so each match counts twice.
+type CardStats struct {
+	Card          string
+	DrawnGames    int     // games in which the card was drawn
+	PlayedGames   int     // games in which the card was played
+	Plays         int     // total number of plays
+	PlayRate      float64 // share of drawn games in which it was played
+	WinRateDrawn  float64
+	WinRatePlayed float64
+	AvgTurnPlayed float64
+	Damage        int // total damage dealt by the card
+	DamagePerPlay float64
+	WinRateDelta  float64 // WinRatePlayed minus the baseline win rate
+	Flagged       bool    // |WinRateDelta| exceeds the threshold and PlayedGames >= MinGames
+
+	winsWhenDrawn  int
+	winsWhenPlayed int
+	turnsPlayed    int
+}
+
+// CardReport is the result of Analyze.
+type CardReport struct {
+	Games        int
+	BaseWinRate  float64 // share of player-games that were won
+	Threshold    float64
+	MinGames     int
+	Cards        []CardStats // sorted by card name
+	FlaggedCards []string
+}
+
+// DefaultMinGames is the number of played games below which a card's win
+// rate is too noisy to flag.
+const DefaultMinGames = 30
+
+// Analyze computes per-card statistics. Cards played in at least minGames
+// games whose win rate when played differs from the baseline by more than
+// threshold are flagged.
+func Analyze(games []Game, threshold float64, minGames int) CardReport {
+	report := CardReport{Games: len(games), Threshold: threshold, MinGames: minGames}
+	stats := make(map[string]*CardStats)
+	get := func(name string) *CardStats {
+		s, ok := stats[name]
+		if !ok {
+			s = &CardStats{Card: name}
+			stats[name] = s
+		}
+		return s
+	}
+
+	wins := 0
+	for _, g := range games {
+		for _, name := range g.Players {
+			won := g.Winner != "" && g.Winner == name
+			if won {
+				wins++
+			}
+
+			drawn := make(map[string]bool)
+			played := make(map[string]bool)
+			for _, e := range g.Events {
+				if e.Player != name || len(e.Cards) == 0 {
+					continue
+				}
+				switch e.Kind {
+				case game.EventDraw:
+					drawn[e.Cards[0]] = true
+				case game.EventPlay:
+					s := get(e.Cards[0])
+					s.Plays++
+					s.turnsPlayed += e.Turn
+					played[e.Cards[0]] = true
+				case game.EventDamage:
+					get(e.Cards[0]).Damage += e.Amount
+				}
+			}
+
+			for card := range drawn {
+				s := get(card)
+				s.DrawnGames++
+				if won {
+					s.winsWhenDrawn++
+				}
+			}
+			for card := range played {
+				s := get(card)
+				s.PlayedGames++
+				if won {
+					s.winsWhenPlayed++
+				}
+			}
+		}
+	}
+
+	if len(games) > 0 {
+		report.BaseWinRate = float64(wins) / float64(2*len(games))
+	}
+	for _, s := range stats {
+		s.PlayRate = ratio(s.PlayedGames, s.DrawnGames)
+		s.WinRateDrawn = ratio(s.winsWhenDrawn, s.DrawnGames)
+		s.WinRatePlayed = ratio(s.winsWhenPlayed, s.PlayedGames)
+		s.AvgTurnPlayed = ratio(s.turnsPlayed, s.Plays)
+		s.DamagePerPlay = ratio(s.Damage, s.Plays)
+		if s.PlayedGames > 0 {
+			s.WinRateDelta = s.WinRatePlayed - report.BaseWinRate
+			s.Flagged = s.PlayedGames >= minGames && math.Abs(s.WinRateDelta) > threshold
+		}
+		report.Cards = append(report.Cards, *s)
+	}
+	sort.Slice(report.Cards, func(i, j int) bool {
+		return report.Cards[i].Card < report.Cards[j].Card
+	})
+	for _, s := range report.Cards {
+		if s.Flagged {
+			report.FlaggedCards = append(report.FlaggedCards, s.Card)
+		}
+	}
+	return report
+}
+
+// WriteCSV writes one row per card with a header row.
+func (r CardReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"card", "drawn_games", "played_games", "plays", "play_rate",
+		"win_rate_drawn", "win_rate_played", "avg_turn_played",
+		"damage", "damage_per_play", "win_rate_delta", "flagged",
+	})
+	for _, s := range r.Cards {
+		cw.Write([]string{
+			s.Card,
+			strconv.Itoa(s.DrawnGames),
+			strconv.Itoa(s.PlayedGames),
+			strconv.Itoa(s.Plays),
+			formatFloat(s.PlayRate),
+			formatFloat(s.WinRateDrawn),
+			formatFloat(s.WinRatePlayed),
+			formatFloat(s.AvgTurnPlayed),
+			strconv.Itoa(s.Damage),
+			formatFloat(s.DamagePerPlay),
+			formatFloat(s.WinRateDelta),
+			strconv.FormatBool(s.Flagged),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
@@ -0,0 +1,93 @@
+package analytics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"GoGame/internal/game"
+)
+
+// played returns a game between a and b in which a draws and plays card on
+// turn 1 and the card deals damage.
+func played(a, b, winner, card string) Game {
+	return Game{
+		Players: [2]string{a, b},
+		Winner:  winner,
+		Events: []game.Event{
+			{Kind: game.EventDraw, Player: a, Cards: []string{card}},
+			{Kind: game.EventPlay, Player: a, Turn: 1, Cards: []string{card}},
+			{Kind: game.EventDamage, Player: a, Cards: []string{card}, Amount: 3},
+			{Kind: game.EventDamage, Player: b, Amount: 1},
+		},
+	}
+}
+
+func find(r CardReport, card string) CardStats {
+	for _, s := range r.Cards {
+		if s.Card == card {
+			return s
+		}
+	}
+	return CardStats{}
+}
+
+func TestAnalyze(t *testing.T) {
+	games := []Game{
+		played("a", "b", "a", "Fireball"),
+		played("a", "b", "a", "Fireball"),
+		played("a", "b", "b", "Heal"),
+		played("a", "b", "", "Heal"),
+	}
+	r := Analyze(games, 0.1, 1)
+	if r.Games != 4 || r.BaseWinRate != 3.0/8 {
+		t.Fatalf("games %d, base win rate %v", r.Games, r.BaseWinRate)
+	}
+
+	fireball := find(r, "Fireball")
+	if fireball.DrawnGames != 2 || fireball.PlayedGames != 2 || fireball.Plays != 2 || fireball.PlayRate != 1 {
+		t.Fatalf("fireball counts %+v", fireball)
+	}
+	if fireball.WinRatePlayed != 1 || fireball.Damage != 6 || fireball.DamagePerPlay != 3 || fireball.AvgTurnPlayed != 1 {
+		t.Fatalf("fireball results %+v", fireball)
+	}
+	if heal := find(r, "Heal"); heal.WinRatePlayed != 0 || heal.WinRateDelta != -3.0/8 {
+		t.Fatalf("heal %+v", heal)
+	}
+	if len(r.Cards) != 2 {
+		t.Fatalf("cards %+v, want damage without a card left out", r.Cards)
+	}
+	if len(r.FlaggedCards) != 2 || r.FlaggedCards[0] != "Fireball" || r.FlaggedCards[1] != "Heal" {
+		t.Fatalf("flagged %v", r.FlaggedCards)
+	}
+}
+
+func TestAnalyzeNeedsEnoughGames(t *testing.T) {
+	games := []Game{
+		played("a", "b", "a", "Fireball"),
+		played("a", "b", "a", "Fireball"),
+		played("a", "b", "b", "Heal"),
+	}
+	r := Analyze(games, 0.1, 3)
+	if len(r.FlaggedCards) != 0 || r.MinGames != 3 {
+		t.Fatalf("flagged %v after two games each", r.FlaggedCards)
+	}
+	if r := Analyze(games, 0.1, 2); len(r.FlaggedCards) != 1 || r.FlaggedCards[0] != "Fireball" {
+		t.Fatalf("flagged %v, want only the card played twice", r.FlaggedCards)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	r := Analyze([]Game{played("a", "b", "a", "Fireball")}, 0.1, 1)
+	if err := r.WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "card,") {
+		t.Fatalf("csv %q", b.String())
+	}
+	if want := "Fireball,1,1,1,1.0000,1.0000,1.0000,1.0000,3,3.0000,0.5000,true"; lines[1] != want {
+		t.Fatalf("row %q, want %q", lines[1], want)
+	}
+}
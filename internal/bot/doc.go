@@ -0,0 +1,36 @@
+// Package bot lets programs written in any language play a seat, in the
+// spirit of chess's UCI. The engine starts the bot as a child process and
+// talks to it over its standard input and output, one command per line.
+// Process is the engine side, a game.Controller; Run is the bot side for
+// bots written in Go. A bot is seated with sim -p1 "bot:<command>" or
+// with the -bot flag of the game; cmd/refbot is a reference bot.
+//
+// # Protocol
+//
+// Engine to bot:
+//
+//	gbp 1                first line; the bot answers with its id and gbpok
+//	newgame              a game starts
+//	isready              the bot answers readyok once it is ready
+//	state {...}          the game as the bot's seat sees it, a game.View as JSON
+//	legal play:0 end     the legal actions right now
+//	go mulligan 1000     choose the cards to redraw within 1000 ms
+//	go action 1000       choose the next action within 1000 ms
+//	rejected <reason>    the last action was illegal; a new go follows
+//	quit                 the bot should exit
+//
+// Bot to engine:
+//
+//	id name Greedy Bot   optional, before gbpok
+//	gbpok
+//	readyok
+//	mulligan 0 3         hand positions to redraw; "mulligan" alone keeps
+//	action play:2        one of play:<hand position>, end, concede
+//	info <text>          logged by the engine at any time
+//
+// Every go is preceded by a state line and answered by exactly one
+// mulligan or action line. A bot that answers too late or with something
+// that cannot be parsed keeps its hand or ends its turn; late answers are
+// discarded. A bot that exits concedes. Unknown lines are ignored in both
+// directions so the protocol can grow.
+package bot
@@ -0,0 +1,305 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"GoGame/internal/game"
+)
+
+// DefaultLimit is the time a bot has for each decision.
+const DefaultLimit = time.Second
+
+// HandshakeTime is how long a bot may take to answer gbp and isready.
+var HandshakeTime = 5 * time.Second
+
+var (
+	errExited  = errors.New("bot exited")
+	errTimeout = errors.New("bot did not answer in time")
+)
+
+// Process is a controller played by an external bot program. The program
+// is started on the first decision, or by Start.
+type Process struct {
+	Path  string
+	Args  []string
+	Limit time.Duration // per decision; 0 uses DefaultLimit
+	// Log receives the bot's info lines, its standard error and protocol
+	// problems; nil discards them.
+	Log io.Writer
+	// Name is the name the bot gave in its id line.
+	Name string
+
+	cmd    *exec.Cmd
+	in     io.WriteCloser
+	lines  chan string // closed when the bot's output ends
+	failed error       // set once the bot cannot play any more
+	inGame bool
+	late   map[string]int // answers still owed for requests that timed out
+}
+
+// New returns the controller for the bot program at path.
+func New(path string, args ...string) *Process {
+	return &Process{Path: path, Args: args}
+}
+
+// Start runs the program and performs the handshake.
+func (p *Process) Start() error {
+	if p.cmd != nil {
+		return p.failed
+	}
+	p.cmd = exec.Command(p.Path, p.Args...)
+	p.cmd.Stderr = p.Log
+	in, err := p.cmd.StdinPipe()
+	if err == nil {
+		p.in = in
+		var out io.ReadCloser
+		if out, err = p.cmd.StdoutPipe(); err == nil {
+			if err = p.cmd.Start(); err == nil {
+				p.lines = make(chan string, 16)
+				go p.read(out)
+			}
+		}
+	}
+	if err != nil {
+		if p.in != nil {
+			p.in.Close()
+			p.in = nil
+		}
+		p.failed = err
+		return err
+	}
+
+	p.send(fmt.Sprintf("gbp %d", Version))
+	if _, err := p.wait("gbpok", HandshakeTime); err != nil {
+		p.fail(fmt.Errorf("handshake: %w", err))
+		return p.failed
+	}
+	return nil
+}
+
+func (p *Process) read(out io.Reader) {
+	defer close(p.lines)
+	scanner := bufio.NewScanner(out)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	for scanner.Scan() {
+		p.lines <- scanner.Text()
+	}
+}
+
+func (p *Process) send(line string) {
+	if p.failed == nil && p.in != nil {
+		io.WriteString(p.in, line+"\n")
+	}
+}
+
+func (p *Process) logf(format string, args ...interface{}) {
+	if p.Log != nil {
+		fmt.Fprintf(p.Log, "%s: %s\n", p.Path, fmt.Sprintf(format, args...))
+	}
+}
+
+// fail stops using the bot until Reset.
+func (p *Process) fail(err error) {
+	if p.failed == nil {
+		p.failed = err
+		p.logf("%v", err)
+		p.stop()
+	}
+}
+
+// wait returns the rest of the next line starting with want. Earlier
+// lines are logged or skipped, and so are the answers owed for earlier
+// requests that timed out.
+func (p *Process) wait(want string, limit time.Duration) (string, error) {
+	timer := time.NewTimer(limit)
+	defer timer.Stop()
+	for {
+		select {
+		case line, ok := <-p.lines:
+			if !ok {
+				return "", errExited
+			}
+			cmd, rest := command(line)
+			if p.late[cmd] > 0 {
+				p.late[cmd]--
+				continue
+			}
+			switch cmd {
+			case want:
+				return rest, nil
+			case "info":
+				p.logf("info %s", rest)
+			case "id":
+				if name, ok := strings.CutPrefix(rest, "name "); ok {
+					p.Name = strings.TrimSpace(name)
+				}
+			}
+		case <-timer.C:
+			if p.late == nil {
+				p.late = make(map[string]int)
+			}
+			p.late[want]++
+			return "", errTimeout
+		}
+	}
+}
+
+// drain discards late answers to earlier requests.
+func (p *Process) drain() {
+	for {
+		select {
+		case line, ok := <-p.lines:
+			if !ok {
+				return
+			}
+			cmd, rest := command(line)
+			if p.late[cmd] > 0 {
+				p.late[cmd]--
+			}
+			if cmd == "info" {
+				p.logf("info %s", rest)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// prepare starts the bot and the game if needed and sends the state. It
+// reports whether the bot can decide.
+func (p *Process) prepare(v game.View) bool {
+	if p.cmd == nil {
+		p.Start()
+	}
+	if p.failed != nil {
+		return false
+	}
+	p.drain()
+	if !p.inGame {
+		p.inGame = true
+		p.send("newgame")
+		p.send("isready")
+		if _, err := p.wait("readyok", HandshakeTime); err != nil {
+			p.fail(fmt.Errorf("newgame: %w", err))
+			return false
+		}
+	}
+	state, err := json.Marshal(v)
+	if err != nil {
+		p.fail(err)
+		return false
+	}
+	p.send("state " + string(state))
+	return true
+}
+
+func (p *Process) limit() time.Duration {
+	if p.Limit > 0 {
+		return p.Limit
+	}
+	return DefaultLimit
+}
+
+func (p *Process) Mulligan(v game.View) []int {
+	if !p.prepare(v) {
+		return nil
+	}
+	p.send(fmt.Sprintf("go mulligan %d", p.limit().Milliseconds()))
+	rest, err := p.wait("mulligan", p.limit())
+	if err == errExited {
+		p.fail(err)
+	}
+	if err != nil {
+		p.logf("mulligan: %v, keeping the hand", err)
+		return nil
+	}
+	indices, err := parseIndices(rest)
+	if err != nil {
+		p.logf("mulligan: %v, keeping the hand", err)
+		return nil
+	}
+	return indices
+}
+
+func (p *Process) NextAction(v game.View) game.Action {
+	if !p.prepare(v) {
+		return game.Action{Kind: game.ActionConcede}
+	}
+	legal := v.LegalActions()
+	words := make([]string, len(legal))
+	for i, a := range legal {
+		words[i] = FormatAction(a)
+	}
+	p.send("legal " + strings.Join(words, " "))
+	p.send(fmt.Sprintf("go action %d", p.limit().Milliseconds()))
+
+	rest, err := p.wait("action", p.limit())
+	if err == errExited {
+		p.fail(err)
+		return game.Action{Kind: game.ActionConcede}
+	}
+	if err != nil {
+		p.logf("action: %v, ending the turn", err)
+		return game.Action{Kind: game.ActionEndTurn}
+	}
+	a, err := ParseAction(rest)
+	if err != nil {
+		p.logf("action: %v, ending the turn", err)
+		return game.Action{Kind: game.ActionEndTurn}
+	}
+	return a
+}
+
+func (p *Process) ActionResult(a game.Action, err error) {
+	if err != nil {
+		p.send("rejected " + err.Error())
+	}
+}
+
+// Reset makes the next decision start a new game, for a Process that is
+// kept across games. A bot that failed is started again.
+func (p *Process) Reset() {
+	p.inGame = false
+	if p.failed != nil {
+		p.cmd, p.lines, p.failed, p.late = nil, nil, nil, nil
+	}
+}
+
+// Close asks the bot to quit and kills it if it does not.
+func (p *Process) Close() error {
+	if p.cmd == nil {
+		return nil
+	}
+	p.send("quit")
+	p.stop()
+	return nil
+}
+
+// stop ends the bot program.
+func (p *Process) stop() {
+	if p.in == nil {
+		return
+	}
+	p.in.Close()
+	p.in = nil
+	done := make(chan struct{})
+	go func() {
+		for range p.lines {
+		}
+		p.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		p.cmd.Process.Kill()
+		<-done
+	}
+}
@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"GoGame/internal/game"
+)
+
+// The tests run their bots as this test binary started with GOGAME_BOT set
+// to the behaviour wanted, see TestHelperBot.
+const helperEnv = "GOGAME_BOT"
+
+// helper is the bot played by the helper process.
+type helper struct{ mode string }
+
+func (h helper) Mulligan(v game.View) []int { return []int{0} }
+
+func (h helper) NextAction(v game.View) game.Action {
+	switch h.mode {
+	case "slow":
+		time.Sleep(200 * time.Millisecond)
+	case "crash":
+		os.Exit(1)
+	}
+	legal := v.LegalActions()
+	return legal[0]
+}
+
+func TestHelperBot(t *testing.T) {
+	mode := os.Getenv(helperEnv)
+	if mode == "" {
+		t.Skip("only runs as a bot for the other tests")
+	}
+	if err := Run(os.Stdin, os.Stdout, "Helper "+mode, helper{mode}); err != nil {
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+func helperBot(t *testing.T, mode string) *Process {
+	t.Helper()
+	t.Setenv(helperEnv, mode)
+	p := New(os.Args[0], "-test.run=^TestHelperBot$")
+	p.Limit = 100 * time.Millisecond
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+// endTurns ends every turn it is asked to play.
+type endTurns struct{}
+
+func (endTurns) Mulligan(v game.View) []int         { return nil }
+func (endTurns) NextAction(v game.View) game.Action { return game.Action{Kind: game.ActionEndTurn} }
+
+// play runs a short game with the bot in the first seat.
+func play(t *testing.T, bot game.Controller) (*game.Game, game.Result) {
+	t.Helper()
+	rules := game.DefaultRules()
+	rules.TurnLimit = 6
+	g, err := game.NewGameWithDecks(game.InitializeDeck(), game.InitializeDeck(), rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.Seed(1)
+	g.SetController(&g.Player1, bot)
+	g.SetController(&g.Player2, endTurns{})
+	g.DealInitialHands()
+	g.GameLoop()
+	return g, g.Result()
+}
+
+func playedCards(g *game.Game, name string) int {
+	n := 0
+	for _, e := range g.Events {
+		if e.Kind == game.EventPlay && e.Player == name {
+			n++
+		}
+	}
+	return n
+}
+
+func TestProcessPlaysAGame(t *testing.T) {
+	p := helperBot(t, "normal")
+	g, result := play(t, p)
+	if result.Reason == game.EndConcede {
+		t.Fatalf("bot conceded: %+v", result)
+	}
+	if p.Name != "Helper normal" {
+		t.Errorf("bot name %q", p.Name)
+	}
+	if playedCards(g, g.Player1.Name) == 0 {
+		t.Error("bot never played a card")
+	}
+}
+
+func TestSlowBotEndsItsTurns(t *testing.T) {
+	p := helperBot(t, "slow")
+	g, result := play(t, p)
+	if result.Reason != game.EndTurnLimit {
+		t.Fatalf("result %+v, want the turn limit", result)
+	}
+	if n := playedCards(g, g.Player1.Name); n != 0 {
+		t.Errorf("a bot that is always late played %d cards", n)
+	}
+}
+
+func TestCrashedBotConcedes(t *testing.T) {
+	p := helperBot(t, "crash")
+	g, result := play(t, p)
+	if result.Reason != game.EndConcede || result.Winner != &g.Player2 {
+		t.Fatalf("result %+v, want a concession", result)
+	}
+}
+
+func TestResetRestartsFailedBot(t *testing.T) {
+	p := helperBot(t, "crash")
+	if _, result := play(t, p); result.Reason != game.EndConcede {
+		t.Fatalf("result %+v, want a concession", result)
+	}
+
+	t.Setenv(helperEnv, "normal")
+	p.Reset()
+	if _, result := play(t, p); result.Reason == game.EndConcede {
+		t.Fatalf("bot still concedes after Reset: %+v", result)
+	}
+}
+
+func TestActionRoundTrip(t *testing.T) {
+	for _, a := range []game.Action{
+		{Kind: game.ActionPlayCard, CardIndex: 3},
+		{Kind: game.ActionEndTurn},
+		{Kind: game.ActionConcede},
+	} {
+		got, err := ParseAction(FormatAction(a))
+		if err != nil || got != a {
+			t.Errorf("%+v came back as %+v, %v", a, got, err)
+		}
+	}
+	if _, err := ParseAction("play:x"); err == nil {
+		t.Error("parsed play:x")
+	}
+}
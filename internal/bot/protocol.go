@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"GoGame/internal/game"
+)
+
+// Version is the protocol version sent with gbp.
+const Version = 1
+
+// FormatAction returns the protocol form of an action.
+func FormatAction(a game.Action) string {
+	switch a.Kind {
+	case game.ActionEndTurn:
+		return "end"
+	case game.ActionConcede:
+		return "concede"
+	}
+	return fmt.Sprintf("play:%d", a.CardIndex)
+}
+
+// ParseAction reads an action in the form written by FormatAction.
+func ParseAction(s string) (game.Action, error) {
+	switch s = strings.TrimSpace(s); s {
+	case "end":
+		return game.Action{Kind: game.ActionEndTurn}, nil
+	case "concede":
+		return game.Action{Kind: game.ActionConcede}, nil
+	}
+	if index, ok := strings.CutPrefix(s, "play:"); ok {
+		i, err := strconv.Atoi(index)
+		if err != nil {
+			return game.Action{}, fmt.Errorf("bad card position in %q", s)
+		}
+		return game.Action{Kind: game.ActionPlayCard, CardIndex: i}, nil
+	}
+	return game.Action{}, fmt.Errorf("unknown action %q", s)
+}
+
+// formatIndices joins hand positions for a mulligan line.
+func formatIndices(indices []int) string {
+	parts := make([]string, len(indices))
+	for i, index := range indices {
+		parts[i] = strconv.Itoa(index)
+	}
+	return strings.Join(parts, " ")
+}
+
+func parseIndices(s string) ([]int, error) {
+	var indices []int
+	for _, field := range strings.Fields(s) {
+		i, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("bad card position %q", field)
+		}
+		indices = append(indices, i)
+	}
+	return indices, nil
+}
+
+// command splits a line into its first word and the rest.
+func command(line string) (cmd, rest string) {
+	line = strings.TrimSpace(line)
+	cmd, rest, _ = strings.Cut(line, " ")
+	return cmd, strings.TrimSpace(rest)
+}
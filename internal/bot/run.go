@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"GoGame/internal/game"
+)
+
+// Run plays the bot side of the protocol: it reads commands from in,
+// asks c for its decisions and writes the answers to out until quit or
+// the end of in. The views c gets are decoded from JSON and only carry
+// their public fields, so View.Simulate is not available; LegalActions is.
+func Run(in io.Reader, out io.Writer, name string, c game.Controller) error {
+	w := bufio.NewWriter(out)
+	reply := func(format string, args ...interface{}) error {
+		fmt.Fprintf(w, format+"\n", args...)
+		return w.Flush()
+	}
+
+	var view game.View
+	var last game.Action
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	for scanner.Scan() {
+		var err error
+		cmd, rest := command(scanner.Text())
+		switch cmd {
+		case "gbp":
+			if name != "" {
+				reply("id name %s", name)
+			}
+			err = reply("gbpok")
+		case "isready":
+			err = reply("readyok")
+		case "newgame":
+			view, last = game.View{}, game.Action{}
+		case "state":
+			view = game.View{}
+			if err := json.Unmarshal([]byte(rest), &view); err != nil {
+				reply("info bad state: %v", err)
+			}
+		case "go":
+			switch kind, _ := command(rest); kind {
+			case "mulligan":
+				err = reply("%s", strings.TrimSpace("mulligan "+formatIndices(c.Mulligan(view))))
+			case "action":
+				last = c.NextAction(view)
+				err = reply("action %s", FormatAction(last))
+			}
+		case "rejected":
+			if o, ok := c.(game.ActionObserver); ok {
+				o.ActionResult(last, errors.New(rest))
+			}
+		case "quit":
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -13,11 +13,16 @@ const (
 )
 
 type Card struct {
-	Name        string
-	Power       int
-	Type        CardType
-	Description string
-	Effect      func(interface{}) // This will be used to implement special card effects
+	ID          int      `json:"id"` // stable identifier used by deck files and deck codes
+	Name        string   `json:"name"`
+	Power       int      `json:"power"`
+	Cost        int      `json:"cost"`
+	Type        CardType `json:"type"`
+	Faction     string   `json:"faction,omitempty"` // empty for neutral cards
+	Description string   `json:"description,omitempty"`
+	// Effect gets everything it acts on through its argument and must not
+	// capture game state, so copies of a card can share it safely.
+	Effect func(interface{}) `json:"-"` // This will be used to implement special card effects
 }
 
 func (c *Card) Play(target interface{}) {
@@ -28,7 +33,7 @@ func (c *Card) Play(target interface{}) {
 }
 
 func (c *Card) GetInfo() string {
-	return fmt.Sprintf("%s (Power: %d)\nType: %s\n%s", c.Name, c.Power, c.getTypeString(), c.Description)
+	return fmt.Sprintf("%s (Power: %d, Cost: %d)\nType: %s\n%s", c.Name, c.Power, c.Cost, c.getTypeString(), c.Description)
 }
 
 func (c *Card) getTypeString() string {
@@ -44,21 +49,47 @@ func (c *Card) getTypeString() string {
 	}
 }
 
-// CreateBasicUnitCard creates a basic unit card with no special effects
+// MarshalText writes the type as "unit", "spell" or "item".
+func (t CardType) MarshalText() ([]byte, error) {
+	switch t {
+	case UnitCard:
+		return []byte("unit"), nil
+	case SpellCard:
+		return []byte("spell"), nil
+	case ItemCard:
+		return []byte("item"), nil
+	}
+	return nil, fmt.Errorf("unknown card type %d", int(t))
+}
+
+func (t *CardType) UnmarshalText(text []byte) error {
+	for _, candidate := range []CardType{UnitCard, SpellCard, ItemCard} {
+		if name, _ := candidate.MarshalText(); string(name) == string(text) {
+			*t = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown card type %q", text)
+}
+
+// CreateBasicUnitCard creates a basic unit card with no special effects.
+// A unit costs as much mana as its power.
 func CreateBasicUnitCard(name string, power int) Card {
 	return Card{
 		Name:        name,
 		Power:       power,
+		Cost:        power,
 		Type:        UnitCard,
 		Description: fmt.Sprintf("A basic unit with %d power.", power),
 	}
 }
 
 // CreateSpellCard creates a spell card with a custom effect
-func CreateSpellCard(name string, description string, effect func(interface{})) Card {
+func CreateSpellCard(name string, cost int, description string, effect func(interface{})) Card {
 	return Card{
 		Name:        name,
 		Power:       0,
+		Cost:        cost,
 		Type:        SpellCard,
 		Description: description,
 		Effect:      effect,
@@ -66,12 +97,33 @@ func CreateSpellCard(name string, description string, effect func(interface{}))
 }
 
 // CreateItemCard creates an item card with a custom effect
-func CreateItemCard(name string, power int, description string, effect func(interface{})) Card {
+func CreateItemCard(name string, power int, cost int, description string, effect func(interface{})) Card {
 	return Card{
 		Name:        name,
 		Power:       power,
+		Cost:        cost,
 		Type:        ItemCard,
 		Description: description,
 		Effect:      effect,
 	}
+}
+
+// Same reports whether two cards have the same data. Effects are not
+// compared: functions cannot be, and cards with the same ID share one.
+func (c Card) Same(o Card) bool {
+	return c.ID == o.ID && c.Name == o.Name && c.Power == o.Power && c.Cost == o.Cost &&
+		c.Type == o.Type && c.Faction == o.Faction && c.Description == o.Description
+}
+
+// Equal reports whether two card lists hold the same cards in the same order.
+func Equal(a, b []Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Same(b[i]) {
+			return false
+		}
+	}
+	return true
 }
\ No newline at end of file
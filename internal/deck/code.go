@@ -0,0 +1,90 @@
+package deck
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Deck codes are URL-safe base64 without padding over this layout:
+//
+//	version  byte
+//	name     uvarint length + UTF-8 bytes
+//	entries  uvarint count, then uvarint card ID and uvarint copies per entry
+var codeEncoding = base64.RawURLEncoding
+
+// maxNameLength bounds the name read from untrusted codes.
+const maxNameLength = 256
+
+// Encode returns the deck code for a list.
+func Encode(list List) string {
+	var buf bytes.Buffer
+	buf.WriteByte(FormatVersion)
+	putUvarint(&buf, uint64(len(list.Name)))
+	buf.WriteString(list.Name)
+	putUvarint(&buf, uint64(len(list.Cards)))
+	for _, e := range list.Cards {
+		putUvarint(&buf, uint64(e.CardID))
+		putUvarint(&buf, uint64(e.Count))
+	}
+	return codeEncoding.EncodeToString(buf.Bytes())
+}
+
+// Decode parses a deck code produced by Encode.
+func Decode(code string) (List, error) {
+	data, err := codeEncoding.DecodeString(strings.TrimSpace(code))
+	if err != nil {
+		return List{}, fmt.Errorf("decode deck code: %w", err)
+	}
+	r := bytes.NewReader(data)
+
+	version, err := r.ReadByte()
+	if err != nil {
+		return List{}, errors.New("decode deck code: empty code")
+	}
+	list := List{Version: int(version)}
+
+	nameLen, err := binary.ReadUvarint(r)
+	if err != nil || nameLen > maxNameLength || nameLen > uint64(r.Len()) {
+		return List{}, errors.New("decode deck code: bad name")
+	}
+	name := make([]byte, nameLen)
+	if _, err := io.ReadFull(r, name); err != nil {
+		return List{}, errors.New("decode deck code: bad name")
+	}
+	list.Name = string(name)
+
+	entries, err := binary.ReadUvarint(r)
+	if err != nil || entries > uint64(r.Len()) {
+		return List{}, errors.New("decode deck code: bad card count")
+	}
+	for i := uint64(0); i < entries; i++ {
+		id, err := binary.ReadUvarint(r)
+		if err != nil {
+			return List{}, errors.New("decode deck code: truncated card list")
+		}
+		count, err := binary.ReadUvarint(r)
+		if err != nil {
+			return List{}, errors.New("decode deck code: truncated card list")
+		}
+		list.Cards = append(list.Cards, Entry{CardID: int(id), Count: int(count)})
+	}
+	if r.Len() != 0 {
+		return List{}, errors.New("decode deck code: trailing data")
+	}
+
+	if err := list.validate(); err != nil {
+		return List{}, fmt.Errorf("decode deck code: %w", err)
+	}
+	return list, nil
+}
+
+func putUvarint(buf *bytes.Buffer, v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	buf.Write(tmp[:n])
+}
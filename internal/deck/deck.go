@@ -0,0 +1,153 @@
+// Package deck defines the shareable deck list format: a JSON deck file and
+// a compact base64 deck code that both encode the same list of card IDs.
+package deck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"GoGame/internal/card"
+)
+
+// FormatVersion is the current version of the deck file and deck code formats.
+const FormatVersion = 1
+
+// MaxCards is the most cards a deck file or code may list. It only keeps
+// a malformed list from expanding without bound and is far above any deck
+// rules; whether a deck is legal is up to game.ValidateDeck.
+const MaxCards = 1000
+
+// ErrNoID is returned by FromCards for a card without an ID, which a deck
+// file or code cannot refer to.
+var ErrNoID = errors.New("card has no id")
+
+// Entry is one card in a deck list together with its number of copies.
+type Entry struct {
+	CardID int `json:"id"`
+	Count  int `json:"count"`
+}
+
+// List is a named deck list.
+type List struct {
+	Version int     `json:"version"`
+	Name    string  `json:"name"`
+	Cards   []Entry `json:"cards"`
+}
+
+// FromCards builds a deck list from concrete cards, grouping copies by ID
+// in the order the cards first appear.
+func FromCards(name string, cards []card.Card) (List, error) {
+	list := List{Version: FormatVersion, Name: name}
+	index := make(map[int]int)
+	for _, c := range cards {
+		if c.ID <= 0 {
+			return List{}, fmt.Errorf("deck %q: %s: %w", name, c.Name, ErrNoID)
+		}
+		if i, ok := index[c.ID]; ok {
+			list.Cards[i].Count++
+			continue
+		}
+		index[c.ID] = len(list.Cards)
+		list.Cards = append(list.Cards, Entry{CardID: c.ID, Count: 1})
+	}
+	return list, nil
+}
+
+// Size returns the total number of cards in the list.
+func (l List) Size() int {
+	size := 0
+	for _, e := range l.Cards {
+		size += e.Count
+	}
+	return size
+}
+
+// Build resolves the list into concrete cards using lookup.
+func (l List) Build(lookup func(id int) (card.Card, bool)) ([]card.Card, error) {
+	var cards []card.Card
+	for _, e := range l.Cards {
+		c, ok := lookup(e.CardID)
+		if !ok {
+			return nil, fmt.Errorf("deck %q: unknown card id %d", l.Name, e.CardID)
+		}
+		for i := 0; i < e.Count; i++ {
+			cards = append(cards, c)
+		}
+	}
+	return cards, nil
+}
+
+// validate checks a list read from a file or code. Each card ID appears in
+// one entry, and the list holds at most MaxCards cards.
+func (l List) validate() error {
+	if l.Version < 1 || l.Version > FormatVersion {
+		return fmt.Errorf("unsupported deck format version %d", l.Version)
+	}
+	seen := make(map[int]bool)
+	total := 0
+	for _, e := range l.Cards {
+		if e.CardID <= 0 {
+			return fmt.Errorf("invalid card id %d", e.CardID)
+		}
+		if seen[e.CardID] {
+			return fmt.Errorf("card id %d listed twice", e.CardID)
+		}
+		seen[e.CardID] = true
+		if e.Count <= 0 {
+			return fmt.Errorf("card id %d: invalid count %d", e.CardID, e.Count)
+		}
+		if e.Count > MaxCards-total {
+			return fmt.Errorf("more than %d cards", MaxCards)
+		}
+		total += e.Count
+	}
+	return nil
+}
+
+// Read decodes a deck file.
+func Read(r io.Reader) (List, error) {
+	var list List
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return List{}, fmt.Errorf("read deck: %w", err)
+	}
+	if err := list.validate(); err != nil {
+		return List{}, fmt.Errorf("read deck: %w", err)
+	}
+	return list, nil
+}
+
+// Write encodes a deck file.
+func Write(w io.Writer, list List) error {
+	if list.Version == 0 {
+		list.Version = FormatVersion
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(list)
+}
+
+// ReadFile reads a deck file from disk.
+func ReadFile(path string) (List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return List{}, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// WriteFile writes a deck file to disk.
+func WriteFile(path string, list List) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, list); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
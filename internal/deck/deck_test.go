@@ -0,0 +1,96 @@
+package deck
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"GoGame/internal/card"
+	"GoGame/internal/game"
+)
+
+func TestCodeRoundTrip(t *testing.T) {
+	cards := append(game.InitializeDeck(), game.InitializeDeck()[:3]...)
+	list, err := FromCards("Starter ✓", cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	back, err := Decode(Encode(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, list) {
+		t.Fatalf("decoded %+v, want %+v", back, list)
+	}
+	built, err := back.Build(game.FindCardByID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Size() != len(cards) || len(built) != len(cards) {
+		t.Fatalf("%d cards built, want %d", len(built), len(cards))
+	}
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	list, err := FromCards("Starter", game.InitializeDeck())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, list); err != nil {
+		t.Fatal(err)
+	}
+	back, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, list) {
+		t.Fatalf("read %+v, want %+v", back, list)
+	}
+}
+
+func TestDecodeRejectsBadLists(t *testing.T) {
+	codes := map[string]string{
+		"duplicate id": Encode(List{Version: FormatVersion, Cards: []Entry{{1, 2}, {2, 1}, {1, 2}}}),
+		"too many":     Encode(List{Version: FormatVersion, Cards: []Entry{{1, MaxCards}, {2, 1}}}),
+		"huge count":   Encode(List{Version: FormatVersion, Cards: []Entry{{1, 10}, {2, math.MaxInt}}}),
+		"zero id":      Encode(List{Version: FormatVersion, Cards: []Entry{{0, 1}}}),
+		"no copies":    Encode(List{Version: FormatVersion, Cards: []Entry{{1, 0}}}),
+		"version":      base64.RawURLEncoding.EncodeToString([]byte{FormatVersion + 1, 0, 0}),
+		// claims a 3-byte name but holds only 2 bytes
+		"short name": base64.RawURLEncoding.EncodeToString([]byte{FormatVersion, 3, 'a', 'b'}),
+		"trailing":   Encode(List{Version: FormatVersion}) + "AA",
+		"not base64": "%%%",
+	}
+	for name, code := range codes {
+		if list, err := Decode(code); err == nil {
+			t.Errorf("%s: decoded %+v", name, list)
+		}
+	}
+}
+
+func TestDecodeLeavesDeckRulesToTheGame(t *testing.T) {
+	list := List{Version: FormatVersion, Cards: []Entry{{1, 30}, {2, 30}}}
+	back, err := Decode(Encode(list))
+	if err != nil {
+		t.Fatalf("a 60 card list did not decode: %v", err)
+	}
+	cards, err := back.Build(game.FindCardByID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(game.ValidateDeck(cards, game.DefaultDeckRules())) == 0 {
+		t.Fatal("the default deck rules accepted 60 cards")
+	}
+}
+
+func TestFromCardsNeedsIDs(t *testing.T) {
+	cards := []card.Card{game.InitializeDeck()[0], card.CreateBasicUnitCard("Custom", 3)}
+	if _, err := FromCards("Custom", cards); !errors.Is(err, ErrNoID) {
+		t.Fatalf("got %v, want ErrNoID", err)
+	}
+}
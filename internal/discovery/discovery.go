@@ -0,0 +1,166 @@
+// Package discovery finds games on the local network. A host broadcasts an
+// announcement on a UDP port at a fixed interval; clients listen on that
+// port for a moment and list what they heard.
+//
+// An announcement is one JSON datagram:
+//
+//	{"magic":"GoGame","name":"Alice's game","ruleset":"default","seats":1,"port":7777}
+//
+// The address to connect to is the sender of the datagram with the
+// announced TCP port.
+package discovery
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DefaultPort is the UDP port announcements are sent to.
+const DefaultPort = 7779
+
+// magic marks announcements of this game among other broadcasts.
+const magic = "GoGame"
+
+// Announcement describes a hosted game.
+type Announcement struct {
+	Magic   string `json:"magic"`
+	Name    string `json:"name"`
+	Ruleset string `json:"ruleset"`
+	Seats   int    `json:"seats"` // open seats
+	Port    int    `json:"port"`  // TCP port of the game server
+}
+
+// Game is a game heard on the network.
+type Game struct {
+	Announcement
+	Addr string // host:port of the game server
+}
+
+// Beacon announces a game until Stop is called.
+type Beacon struct {
+	// To is where announcements are sent, by default the IPv4 broadcast
+	// address on DefaultPort.
+	To       string
+	Interval time.Duration
+	// Info returns the announcement to send; it is called for every one so
+	// the open seats stay current.
+	Info func() Announcement
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Start sends the first announcement and keeps announcing in the
+// background.
+func (b *Beacon) Start() error {
+	to := b.To
+	if to == "" {
+		to = net.JoinHostPort(net.IPv4bcast.String(), strconv.Itoa(DefaultPort))
+	}
+	conn, err := net.Dial("udp4", to)
+	if err != nil {
+		return err
+	}
+	interval := b.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if err := b.send(conn); err != nil {
+		conn.Close()
+		return err
+	}
+
+	b.stop, b.done = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(b.done)
+		defer conn.Close()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.send(conn)
+			case <-b.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (b *Beacon) send(conn net.Conn) error {
+	a := b.Info()
+	a.Magic = magic
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(data)
+	return err
+}
+
+// Stop ends the announcements.
+func (b *Beacon) Stop() {
+	if b.stop != nil {
+		close(b.stop)
+		<-b.done
+		b.stop = nil
+	}
+}
+
+// Browse listens on conn for wait and returns the games it heard, each once
+// with its latest announcement, ordered by name.
+func Browse(conn net.PacketConn, wait time.Duration) ([]Game, error) {
+	found := make(map[string]Game)
+	deadline := time.Now().Add(wait)
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 2048)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				break
+			}
+			return nil, err
+		}
+		var a Announcement
+		if json.Unmarshal(buf[:n], &a) != nil || a.Magic != magic || a.Port <= 0 {
+			continue
+		}
+		udp, ok := from.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+		addr := net.JoinHostPort(udp.IP.String(), strconv.Itoa(a.Port))
+		found[addr] = Game{Announcement: a, Addr: addr}
+	}
+
+	games := make([]Game, 0, len(found))
+	for _, g := range found {
+		games = append(games, g)
+	}
+	sort.Slice(games, func(i, j int) bool {
+		if games[i].Name != games[j].Name {
+			return games[i].Name < games[j].Name
+		}
+		return games[i].Addr < games[j].Addr
+	})
+	return games, nil
+}
+
+// Scan listens on DefaultPort for wait and returns the games it heard.
+func Scan(wait time.Duration) ([]Game, error) {
+	conn, err := net.ListenPacket("udp4", ":"+strconv.Itoa(DefaultPort))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return Browse(conn, wait)
+}
@@ -0,0 +1,65 @@
+package discovery
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestBrowseHearsBeaconsOnLoopback(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	to := conn.LocalAddr().String()
+
+	alice := &Beacon{To: to, Interval: 20 * time.Millisecond, Info: func() Announcement {
+		return Announcement{Name: "Alice's game", Ruleset: "default", Seats: 2, Port: 7777}
+	}}
+	bob := &Beacon{To: to, Interval: 20 * time.Millisecond, Info: func() Announcement {
+		return Announcement{Name: "Bob's game", Ruleset: "legacy", Seats: 1, Port: 7000}
+	}}
+	for _, b := range []*Beacon{alice, bob} {
+		if err := b.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer b.Stop()
+	}
+
+	// other traffic on the port is ignored
+	noise, err := net.Dial("udp4", to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer noise.Close()
+	noise.Write([]byte("hello"))
+	noise.Write([]byte(`{"magic":"Other","name":"x","port":1}`))
+
+	games, err := Browse(conn, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("found %+v", games)
+	}
+	a, b := games[0], games[1]
+	if a.Name != "Alice's game" || a.Addr != "127.0.0.1:7777" || a.Seats != 2 || a.Ruleset != "default" {
+		t.Fatalf("first game %+v", a)
+	}
+	if b.Name != "Bob's game" || b.Addr != "127.0.0.1:7000" || b.Ruleset != "legacy" {
+		t.Fatalf("second game %+v", b)
+	}
+}
+
+func TestBrowseFindsNothing(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	games, err := Browse(conn, 50*time.Millisecond)
+	if err != nil || len(games) != 0 {
+		t.Fatalf("found %+v, %v", games, err)
+	}
+}
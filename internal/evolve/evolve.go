@@ -0,0 +1,257 @@
+// Package evolve searches for strong decks with a genetic algorithm. Decks
+// are scored by playing them without a window against a gauntlet of
+// reference decks and computer opponents.
+package evolve
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"GoGame/internal/card"
+	"GoGame/internal/game"
+	"GoGame/internal/sim"
+)
+
+// Opponent is one entry of the gauntlet.
+type Opponent struct {
+	Name       string
+	Deck       []card.Card
+	Controller sim.ControllerFactory
+}
+
+// Config controls a run. The same Config, including Seed, always produces
+// the same result.
+type Config struct {
+	Pool        []card.Card // cards decks are built from
+	Rules       game.Rules
+	DeckSize    int
+	Population  int
+	Generations int
+	Elite       int     // best decks copied unchanged into the next generation
+	Mutation    float64 // chance that a child gets a mutation
+	Games       int     // games per opponent and seat
+	Gauntlet    []Opponent
+	Player      sim.ControllerFactory // plays the evolved decks
+	Seed        int64
+	Workers     int // parallel fitness evaluations, 0 for one per CPU
+
+	// Progress, if set, is called after every generation.
+	Progress func(generation int, best Individual)
+}
+
+// Individual is a deck with its fitness.
+type Individual struct {
+	Counts  []int   // copies of each Pool card
+	Fitness float64 // win rate against the gauntlet
+}
+
+// Cards expands the counts into a deck.
+func (ind Individual) Cards(pool []card.Card) []card.Card {
+	var deck []card.Card
+	for i, n := range ind.Counts {
+		for j := 0; j < n; j++ {
+			deck = append(deck, pool[i])
+		}
+	}
+	return deck
+}
+
+// Run evolves decks and returns the final population, best first.
+func Run(cfg Config) ([]Individual, error) {
+	if err := cfg.check(); err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	population := make([]Individual, cfg.Population)
+	for i := range population {
+		population[i] = Individual{Counts: cfg.randomCounts(rng)}
+	}
+
+	for gen := 0; gen < cfg.Generations; gen++ {
+		if err := cfg.evaluate(population, rng.Int63()); err != nil {
+			return nil, err
+		}
+		sort.SliceStable(population, func(i, j int) bool {
+			return population[i].Fitness > population[j].Fitness
+		})
+		if cfg.Progress != nil {
+			cfg.Progress(gen, population[0])
+		}
+		if gen == cfg.Generations-1 {
+			break
+		}
+
+		next := make([]Individual, 0, cfg.Population)
+		for i := 0; i < cfg.Elite && i < len(population); i++ {
+			next = append(next, Individual{Counts: append([]int(nil), population[i].Counts...)})
+		}
+		for len(next) < cfg.Population {
+			a := tournament(population, rng)
+			b := tournament(population, rng)
+			child := cfg.crossover(a.Counts, b.Counts, rng)
+			if rng.Float64() < cfg.Mutation {
+				cfg.mutate(child, rng)
+			}
+			next = append(next, Individual{Counts: child})
+		}
+		population = next
+	}
+	return population, nil
+}
+
+func (cfg *Config) check() error {
+	if len(cfg.Pool) == 0 {
+		return fmt.Errorf("evolve: empty card pool")
+	}
+	if cfg.Population < 2 || cfg.Generations < 1 || cfg.Games < 1 {
+		return fmt.Errorf("evolve: population, generations and games must be positive")
+	}
+	if len(cfg.Gauntlet) == 0 {
+		return fmt.Errorf("evolve: empty gauntlet")
+	}
+	if max := cfg.maxCopies() * len(cfg.Pool); cfg.DeckSize > max {
+		return fmt.Errorf("evolve: deck size %d exceeds %d allowed by copy limits", cfg.DeckSize, max)
+	}
+	if cfg.DeckSize < cfg.Rules.Deck.MinSize || (cfg.Rules.Deck.MaxSize > 0 && cfg.DeckSize > cfg.Rules.Deck.MaxSize) {
+		return fmt.Errorf("evolve: deck size %d breaks the deck rules", cfg.DeckSize)
+	}
+	return nil
+}
+
+func (cfg *Config) maxCopies() int {
+	if cfg.Rules.Deck.MaxCopies > 0 {
+		return cfg.Rules.Deck.MaxCopies
+	}
+	return cfg.DeckSize
+}
+
+func (cfg *Config) randomCounts(rng *rand.Rand) []int {
+	counts := make([]int, len(cfg.Pool))
+	cfg.repair(counts, rng)
+	return counts
+}
+
+// repair adds or removes random copies until the deck has DeckSize cards
+// and no card exceeds the copy limit.
+func (cfg *Config) repair(counts []int, rng *rand.Rand) {
+	limit := cfg.maxCopies()
+	size := 0
+	for i := range counts {
+		if counts[i] > limit {
+			counts[i] = limit
+		}
+		size += counts[i]
+	}
+	for size > cfg.DeckSize {
+		if i := rng.Intn(len(counts)); counts[i] > 0 {
+			counts[i]--
+			size--
+		}
+	}
+	for size < cfg.DeckSize {
+		if i := rng.Intn(len(counts)); counts[i] < limit {
+			counts[i]++
+			size++
+		}
+	}
+}
+
+func (cfg *Config) crossover(a, b []int, rng *rand.Rand) []int {
+	child := make([]int, len(a))
+	for i := range child {
+		if rng.Intn(2) == 0 {
+			child[i] = a[i]
+		} else {
+			child[i] = b[i]
+		}
+	}
+	cfg.repair(child, rng)
+	return child
+}
+
+// mutate swaps one copy of a card for a copy of another.
+func (cfg *Config) mutate(counts []int, rng *rand.Rand) {
+	from, to := rng.Intn(len(counts)), rng.Intn(len(counts))
+	if from == to || counts[from] == 0 || counts[to] >= cfg.maxCopies() {
+		return
+	}
+	counts[from]--
+	counts[to]++
+}
+
+func tournament(population []Individual, rng *rand.Rand) Individual {
+	a := population[rng.Intn(len(population))]
+	b := population[rng.Intn(len(population))]
+	if b.Fitness > a.Fitness {
+		return b
+	}
+	return a
+}
+
+// evaluate plays every individual against the whole gauntlet from both
+// seats. Game seeds come from seed, so scheduling does not change results.
+func (cfg *Config) evaluate(population []Individual, seed int64) error {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	errs := make([]error, len(population))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				population[i].Fitness, errs[i] = cfg.fitness(population[i], seed+int64(i)*1000003)
+			}
+		}()
+	}
+	for i := range population {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cfg *Config) fitness(ind Individual, seed int64) (float64, error) {
+	deck := ind.Cards(cfg.Pool)
+	if violations := game.ValidateDeck(deck, cfg.Rules.Deck); len(violations) > 0 {
+		return 0, fmt.Errorf("evolve: generated an invalid deck: %s", violations[0])
+	}
+
+	wins, games := 0.0, 0
+	for _, opp := range cfg.Gauntlet {
+		for seat := 0; seat < 2; seat++ {
+			match := sim.Match{Rules: cfg.Rules}
+			match.Decks[seat], match.Controllers[seat] = deck, cfg.Player
+			match.Decks[1-seat], match.Controllers[1-seat] = opp.Deck, opp.Controller
+			for i := 0; i < cfg.Games; i++ {
+				rec, err := sim.Play(match, seed)
+				seed++
+				if err != nil {
+					return 0, err
+				}
+				switch rec.Winner {
+				case seat:
+					wins++
+				case -1:
+					wins += 0.5
+				}
+				games++
+			}
+		}
+	}
+	return wins / float64(games), nil
+}
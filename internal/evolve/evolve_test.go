@@ -0,0 +1,131 @@
+package evolve
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"GoGame/internal/game"
+	"GoGame/internal/sim"
+)
+
+func config(t *testing.T) Config {
+	t.Helper()
+	random, err := sim.NamedController("random")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := sim.Rules()
+	rules.TurnLimit = 20
+	return Config{
+		Pool:        game.InitializeDeck(),
+		Rules:       rules,
+		DeckSize:    10,
+		Population:  4,
+		Generations: 2,
+		Elite:       1,
+		Mutation:    0.5,
+		Games:       1,
+		Gauntlet:    []Opponent{{Name: "random", Deck: game.InitializeDeck(), Controller: random}},
+		Player:      random,
+		Seed:        1,
+		Workers:     2,
+	}
+}
+
+// valid checks that counts make a deck of the configured size that keeps
+// the copy limit.
+func valid(t *testing.T, cfg Config, counts []int) {
+	t.Helper()
+	size := 0
+	for i, n := range counts {
+		if n < 0 || n > cfg.maxCopies() {
+			t.Fatalf("%d copies of card %d in %v", n, i, counts)
+		}
+		size += n
+	}
+	if size != cfg.DeckSize {
+		t.Fatalf("deck of %d cards %v, want %d", size, counts, cfg.DeckSize)
+	}
+}
+
+func TestRepair(t *testing.T) {
+	cfg := config(t)
+	rng := rand.New(rand.NewSource(1))
+	n := len(cfg.Pool)
+
+	over := make([]int, n)
+	over[0], over[1], over[2], over[3], over[4] = 9, 3, 3, 3, 3
+	cfg.repair(over, rng)
+	valid(t, cfg, over)
+
+	under := make([]int, n)
+	under[0] = 5
+	cfg.repair(under, rng)
+	valid(t, cfg, under)
+	if under[0] != 3 {
+		t.Fatalf("card over the limit kept %d copies", under[0])
+	}
+
+	ok := cfg.randomCounts(rng)
+	before := slices.Clone(ok)
+	cfg.repair(ok, rng)
+	if !slices.Equal(ok, before) {
+		t.Fatalf("repair changed a valid deck %v into %v", before, ok)
+	}
+}
+
+func TestOffspringStayValid(t *testing.T) {
+	cfg := config(t)
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		a, b := cfg.randomCounts(rng), cfg.randomCounts(rng)
+		valid(t, cfg, a)
+		child := cfg.crossover(a, b, rng)
+		valid(t, cfg, child)
+		cfg.mutate(child, rng)
+		valid(t, cfg, child)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cfg := config(t)
+	if err := cfg.check(); err != nil {
+		t.Fatal(err)
+	}
+	tooBig := cfg
+	tooBig.DeckSize = cfg.Rules.Deck.MaxCopies*len(cfg.Pool) + 1
+	if err := tooBig.check(); err == nil {
+		t.Fatal("accepted a deck size the copy limit cannot reach")
+	}
+	tooSmall := cfg
+	tooSmall.DeckSize = cfg.Rules.Deck.MinSize - 1
+	if err := tooSmall.check(); err == nil {
+		t.Fatal("accepted a deck size below the deck rules")
+	}
+	if _, err := Run(Config{}); err == nil {
+		t.Fatal("ran with an empty config")
+	}
+}
+
+func TestRunIsReproducible(t *testing.T) {
+	cfg := config(t)
+	first, err := Run(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Workers = 1
+	second, err := Run(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range first {
+		valid(t, cfg, first[i].Counts)
+		if !slices.Equal(first[i].Counts, second[i].Counts) || first[i].Fitness != second[i].Fitness {
+			t.Fatalf("individual %d differs: %+v and %+v", i, first[i], second[i])
+		}
+		if i > 0 && first[i].Fitness > first[i-1].Fitness {
+			t.Fatal("population not sorted best first")
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package fairplay lets the players of a game run by a host they do not
+// trust check that no shuffle was rigged, with a commit-reveal seed:
+//
+//  1. Each seat picks a random Secret and sends only its Commitment.
+//  2. Once both commitments and the digest of the deck lists are known to
+//     both seats, each seat reveals its secret to the host. Neither side
+//     can choose its secret after seeing the other's, so neither controls
+//     the seed the host derives from both with Seed.
+//  3. When the game is over the host publishes a Proof: both secrets, the
+//     deck lists and the full record. Each seat replays the game from it
+//     and checks every event it saw, every draw included.
+//
+// The scheme keeps the host from rigging the shuffle, not from peeking at
+// it. The host runs the game, so it learns both secrets, and with them every
+// hand and the order of both decks, before the first turn. A host that also
+// plays a seat, or tells a player what it knows, sees the opponent's cards,
+// and nothing here can detect that. It could also abandon a game whose seed
+// it dislikes before the first turn. The players only learn the seed at the
+// end, so the proof gives away nothing to them while the game is played.
+//
+// A seat the host plays itself, such as a computer opponent, gets a secret
+// the host picks. Its commitment still reaches the other seat before that
+// seat reveals, and Verify checks the secret against it, so the host cannot
+// choose the seed through it either. Such a seat is part of the host,
+// though, and may play knowing the whole shuffle.
+package fairplay
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"GoGame/internal/card"
+)
+
+// Secret is the random contribution of one seat to the shuffle seed.
+type Secret [32]byte
+
+// Hash is a SHA-256 digest: a commitment to a secret or to deck lists.
+type Hash [32]byte
+
+// NewSecret returns a secret from the system's secure random source.
+func NewSecret() Secret {
+	var s Secret
+	if _, err := rand.Read(s[:]); err != nil {
+		panic(fmt.Sprintf("fairplay: no random source: %v", err))
+	}
+	return s
+}
+
+// Commit returns the commitment to s that a seat sends before revealing s.
+func (s Secret) Commit() Hash {
+	return digest("gogame/commit", s[:])
+}
+
+// Seed combines the secrets of both seats into the seed of the game.
+func Seed(seat1, seat2 Secret) int64 {
+	h := digest("gogame/seed", seat1[:], seat2[:])
+	return int64(binary.BigEndian.Uint64(h[:8]))
+}
+
+// DeckIDs returns the card IDs of both deck lists, in the order the game
+// shuffles them.
+func DeckIDs(decks [2][]card.Card) [2][]int {
+	var ids [2][]int
+	for i, deck := range decks {
+		ids[i] = make([]int, len(deck))
+		for j, c := range deck {
+			ids[i][j] = c.ID
+		}
+	}
+	return ids
+}
+
+// DeckDigest returns the digest of both deck lists. The host announces it
+// before the secrets are revealed, so it cannot reorder a deck list to
+// steer a shuffle once it knows the seed.
+func DeckDigest(ids [2][]int) Hash {
+	data, _ := json.Marshal(ids)
+	return digest("gogame/decks", data)
+}
+
+func digest(domain string, parts ...[]byte) Hash {
+	h := sha256.New()
+	h.Write([]byte(domain))
+	for _, p := range parts {
+		h.Write(p)
+	}
+	var out Hash
+	h.Sum(out[:0])
+	return out
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(s[:])), nil
+}
+
+func (s *Secret) UnmarshalText(text []byte) error {
+	return decodeHex(s[:], text)
+}
+
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *Hash) UnmarshalText(text []byte) error {
+	return decodeHex(h[:], text)
+}
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+func decodeHex(dst, text []byte) error {
+	if hex.DecodedLen(len(text)) != len(dst) {
+		return fmt.Errorf("want %d hex digits, got %d", 2*len(dst), len(text))
+	}
+	_, err := hex.Decode(dst, text)
+	return err
+}
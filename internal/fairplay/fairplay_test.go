@@ -0,0 +1,136 @@
+package fairplay
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"GoGame/internal/card"
+	"GoGame/internal/game"
+	"GoGame/internal/player"
+)
+
+// playSeeded plays a game between random controllers with the given seed,
+// as a host would after the exchange.
+func playSeeded(t *testing.T, seed int64) *game.Game {
+	t.Helper()
+	g, err := game.NewGameWithDecks(game.InitializeDeck(), game.InitializeDeck(), game.DefaultRules())
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.Player1.Name, g.Player2.Name = "Alice", "Bob"
+	g.Seed(seed)
+	g.SetController(&g.Player1, game.NewRandomController(rand.New(rand.NewSource(1))))
+	g.SetController(&g.Player2, game.NewRandomController(rand.New(rand.NewSource(2))))
+	g.DealInitialHands()
+	g.GameLoop()
+	return g
+}
+
+// roundTrip sends v through JSON into out, as the server would.
+func roundTrip(t *testing.T, v, out interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checks returns what each seat knows of g before the proof.
+func checks(t *testing.T, g *game.Game, secrets [2]Secret) [2]Check {
+	t.Helper()
+	digest := DeckDigest(DeckIDs([2][]card.Card{g.DeckList(&g.Player1), g.DeckList(&g.Player2)}))
+	var out [2]Check
+	for i, p := range []*player.Player{&g.Player1, &g.Player2} {
+		out[i] = Check{Seat: i + 1, Secret: secrets[i], Opponent: secrets[1-i].Commit(), Decks: digest}
+		roundTrip(t, g.EventsFor(p, 0), &out[i].Events)
+	}
+	return out
+}
+
+func proofOf(t *testing.T, g *game.Game, secrets [2]Secret) Proof {
+	t.Helper()
+	var p Proof
+	roundTrip(t, NewProof(g, secrets), &p)
+	return p
+}
+
+func wantCheat(t *testing.T, err error, what string) {
+	t.Helper()
+	var cheat *CheatError
+	if !errors.As(err, &cheat) {
+		t.Errorf("%s: got %v, want cheating to be detected", what, err)
+	}
+}
+
+func TestHonestGameVerifies(t *testing.T) {
+	secrets := [2]Secret{NewSecret(), NewSecret()}
+	g := playSeeded(t, Seed(secrets[0], secrets[1]))
+	proof := proofOf(t, g, secrets)
+	for _, c := range checks(t, g, secrets) {
+		if err := c.Verify(proof); err != nil {
+			t.Errorf("seat %d: %v", c.Seat, err)
+		}
+	}
+}
+
+func TestRiggedShuffleIsDetected(t *testing.T) {
+	secrets := [2]Secret{NewSecret(), NewSecret()}
+	// the host plays with a seed of its own choosing instead
+	g := playSeeded(t, 42)
+	proof := proofOf(t, g, secrets)
+	for _, c := range checks(t, g, secrets) {
+		wantCheat(t, c.Verify(proof), "rigged seed")
+	}
+}
+
+func TestTamperedProofIsDetected(t *testing.T) {
+	secrets := [2]Secret{NewSecret(), NewSecret()}
+	g := playSeeded(t, Seed(secrets[0], secrets[1]))
+	c := checks(t, g, secrets)[0]
+
+	p := proofOf(t, g, secrets)
+	p.Secrets[0] = NewSecret()
+	wantCheat(t, c.Verify(p), "our secret replaced")
+
+	p = proofOf(t, g, secrets)
+	p.Secrets[1] = NewSecret()
+	wantCheat(t, c.Verify(p), "opponent's secret replaced")
+
+	p = proofOf(t, g, secrets)
+	p.Decks[1][0], p.Decks[1][1] = p.Decks[1][1], p.Decks[1][0]
+	wantCheat(t, c.Verify(p), "deck list reordered")
+
+	// a draw that was sent to the seat but differs from the replay
+	p = proofOf(t, g, secrets)
+	for i, e := range c.Events {
+		if e.Kind == game.EventDraw && len(e.Cards) > 0 {
+			c.Events[i].Cards = []string{e.Cards[0] + "?"}
+			break
+		}
+	}
+	wantCheat(t, c.Verify(p), "draw changed")
+}
+
+func TestCommitmentText(t *testing.T) {
+	s := NewSecret()
+	var back Secret
+	roundTrip(t, s, &back)
+	if back != s {
+		t.Fatal("secret changed in JSON")
+	}
+	var h Hash
+	if err := h.UnmarshalText([]byte("abc")); err == nil {
+		t.Fatal("accepted a short commitment")
+	}
+	other := NewSecret()
+	if Seed(s, other) == Seed(other, s) || Seed(s, other) == Seed(s, NewSecret()) {
+		t.Fatal("seed does not depend on both secrets in seat order")
+	}
+}
@@ -0,0 +1,195 @@
+package fairplay
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"GoGame/internal/card"
+	"GoGame/internal/game"
+)
+
+// Proof is what the host publishes at the end of a game: enough to replay
+// it exactly.
+type Proof struct {
+	Secrets [2]Secret    `json:"secrets"`
+	Decks   [2][]int     `json:"decks"` // card IDs of the deck lists
+	Names   [2]string    `json:"names"`
+	Rules   game.Rules   `json:"rules"`
+	Turns   int          `json:"turns"`
+	Events  []game.Event `json:"events"` // the full record, hidden cards included
+}
+
+// NewProof returns the proof of a finished game seeded with the secrets.
+func NewProof(g *game.Game, secrets [2]Secret) Proof {
+	return Proof{
+		Secrets: secrets,
+		Decks:   DeckIDs([2][]card.Card{g.DeckList(&g.Player1), g.DeckList(&g.Player2)}),
+		Names:   [2]string{g.Player1.Name, g.Player2.Name},
+		Rules:   g.Rules,
+		Turns:   g.TurnCount,
+		Events:  g.Events,
+	}
+}
+
+// CheatError reports a proof that does not hold up: the host or the
+// opponent tampered with the seed, the decks or the game.
+type CheatError struct {
+	Reason string
+}
+
+func (e *CheatError) Error() string {
+	return "cheating detected: " + e.Reason
+}
+
+func cheat(format string, args ...interface{}) error {
+	return &CheatError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// Check is what one seat knows before it sees the proof.
+type Check struct {
+	Seat     int    // 1 or 2
+	Secret   Secret // the seat's own secret
+	Opponent Hash   // the commitment the opponent sent
+	Decks    Hash   // the deck digest announced by the host
+	// Events are the events the seat received during the game, as
+	// projected by game.EventsFor.
+	Events []game.Event
+}
+
+// Verify replays the game of the proof and checks it against what the seat
+// saw. Any mismatch is reported as a *CheatError.
+func (c Check) Verify(p Proof) error {
+	if c.Seat != 1 && c.Seat != 2 {
+		return fmt.Errorf("fairplay: seat %d", c.Seat)
+	}
+	own, other := c.Seat-1, 2-c.Seat
+	if p.Secrets[own] != c.Secret {
+		return cheat("the game was not seeded with our secret")
+	}
+	if p.Secrets[other].Commit() != c.Opponent {
+		return cheat("the opponent's secret does not match its commitment")
+	}
+	if DeckDigest(p.Decks) != c.Decks {
+		return cheat("the deck lists are not the ones announced")
+	}
+
+	g, err := Replay(p)
+	if err != nil {
+		return cheat("%v", err)
+	}
+	if i, ok := sameEvents(g.Events, p.Events); !ok {
+		return cheat("%s of the record does not follow from the seed", describe(p.Events, i))
+	}
+	self := &g.Player1
+	if c.Seat == 2 {
+		self = &g.Player2
+	}
+	if i, ok := sameEvents(g.EventsFor(self, 0), c.Events); !ok {
+		return cheat("%s we were sent does not follow from the seed", describe(c.Events, i))
+	}
+	return nil
+}
+
+// Replay plays the game of the proof again, seeded with its secrets and
+// with every decision taken from its record.
+func Replay(p Proof) (*game.Game, error) {
+	var decks [2][]card.Card
+	for i, ids := range p.Decks {
+		for _, id := range ids {
+			c, ok := game.FindCardByID(id)
+			if !ok {
+				return nil, fmt.Errorf("unknown card id %d", id)
+			}
+			decks[i] = append(decks[i], c)
+		}
+	}
+	g, err := game.NewGameWithDecks(decks[0], decks[1], p.Rules)
+	if err != nil {
+		return nil, err
+	}
+	g.Output = io.Discard
+	g.Player1.Name, g.Player2.Name = p.Names[0], p.Names[1]
+	g.Seed(Seed(p.Secrets[0], p.Secrets[1]))
+	g.SetController(&g.Player1, newScript(p, p.Names[0]))
+	g.SetController(&g.Player2, newScript(p, p.Names[1]))
+	g.DealInitialHands()
+	g.GameLoop()
+	return g, nil
+}
+
+// script repeats the decisions a player took in a recorded game.
+type script struct {
+	mulligan []int
+	plays    map[int][]string // names of the cards played, by turn
+	concede  map[int]bool
+	turns    int
+}
+
+func newScript(p Proof, name string) *script {
+	s := &script{plays: make(map[int][]string), concede: make(map[int]bool), turns: p.Turns}
+	for _, e := range p.Events {
+		if e.Player != name {
+			continue
+		}
+		switch e.Kind {
+		case game.EventMulligan:
+			s.mulligan = e.Indices
+		case game.EventPlay:
+			s.plays[e.Turn] = append(s.plays[e.Turn], e.Cards...)
+		case game.EventConcede:
+			s.concede[e.Turn] = true
+		}
+	}
+	return s
+}
+
+func (s *script) Mulligan(v game.View) []int {
+	return s.mulligan
+}
+
+func (s *script) NextAction(v game.View) game.Action {
+	if v.Turn > s.turns {
+		// the record ends here; a faithful replay never gets this far
+		return game.Action{Kind: game.ActionConcede}
+	}
+	if plays := s.plays[v.Turn]; len(plays) > 0 {
+		s.plays[v.Turn] = plays[1:]
+		for i, c := range v.Self.Hand {
+			if c.Name == plays[0] {
+				return game.Action{Kind: game.ActionPlayCard, CardIndex: i}
+			}
+		}
+		return game.Action{Kind: game.ActionConcede}
+	}
+	if s.concede[v.Turn] {
+		return game.Action{Kind: game.ActionConcede}
+	}
+	return game.Action{Kind: game.ActionEndTurn}
+}
+
+// sameEvents compares two event lists and returns the index of the first
+// difference. Empty and missing lists are the same, as after a JSON round
+// trip.
+func sameEvents(a, b []game.Event) (int, bool) {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if i >= len(a) || i >= len(b) {
+			return i, false
+		}
+		x, y := a[i], b[i]
+		if x.Turn != y.Turn || x.Kind != y.Kind || x.Player != y.Player ||
+			x.Amount != y.Amount || x.Message != y.Message ||
+			!slices.Equal(x.Cards, y.Cards) || !slices.Equal(x.Indices, y.Indices) {
+			return i, false
+		}
+	}
+	return 0, true
+}
+
+// describe names event i of a list for a report.
+func describe(events []game.Event, i int) string {
+	if i >= len(events) {
+		return fmt.Sprintf("the end of the game after event %d", i)
+	}
+	return fmt.Sprintf("event %d (%s on turn %d)", i, events[i].Kind, events[i].Turn)
+}
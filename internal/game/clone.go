@@ -0,0 +1,277 @@
+package game
+
+import (
+	"math/rand"
+
+	"GoGame/internal/card"
+)
+
+// Clone returns a fully independent copy of the game state: players, their
+// hands, items and modifiers, the field, decks, rules, events and the last
+// play are all copied, and CurrentPlayer and the field point at the copy's
+// own players. A Rand created by Seed is copied too, so the clone shuffles
+// exactly as the original would without advancing it. UI hooks and
+// controllers are not copied: controllers keep per-game state such as a
+// plan or a waiting human, so the clone has none until SetController is
+// called. Output and any other Rand are shared.
+func (g *Game) Clone() *Game {
+	c := &Game{
+		Player1:      copyPlayer(&g.Player1),
+		Player2:      copyPlayer(&g.Player2),
+		LastPlay:     g.LastPlay.clone(),
+		TurnCount:    g.TurnCount,
+		GameOver:     g.GameOver,
+		CurrentPhase: g.CurrentPhase,
+		Rules:        g.Rules.clone(),
+		Events:       make([]Event, len(g.Events)),
+		Output:       g.Output,
+		Rand:         g.Rand,
+
+		mulliganDone:  g.mulliganDone,
+		fatigueDamage: g.fatigueDamage,
+		deckedOut:     g.deckedOut,
+		conceded:      g.conceded,
+		zones:         g.zones,
+	}
+	for i, e := range g.Events {
+		c.Events[i] = e.clone()
+	}
+	for i, deck := range g.deckLists {
+		c.deckLists[i] = append([]card.Card(nil), deck...)
+	}
+	if g.source != nil && g.Rand == g.seeded {
+		source := *g.source
+		c.source = &source
+		c.seeded = rand.New(c.source)
+		c.Rand = c.seeded
+	}
+
+	c.CurrentPlayer = &c.Player1
+	if g.CurrentPlayer == &g.Player2 {
+		c.CurrentPlayer = &c.Player2
+	}
+	if g.Field != nil {
+		c.Field = g.Field.clone(&c.Player1, &c.Player2)
+	}
+	return c
+}
+
+func (r PlayResult) clone() PlayResult {
+	r.Calculations = append([]Calculation(nil), r.Calculations...)
+	for i, calc := range r.Calculations {
+		r.Calculations[i].Lines = append([]BonusLine(nil), calc.Lines...)
+	}
+	return r
+}
+
+func (r Rules) clone() Rules {
+	r.Deck.Factions = append([]string(nil), r.Deck.Factions...)
+	r.Deck.Types = append([]card.CardType(nil), r.Deck.Types...)
+	r.Deck.Banned = append([]string(nil), r.Deck.Banned...)
+	return r
+}
+
+func (e Event) clone() Event {
+	e.Cards = append([]string(nil), e.Cards...)
+	e.Indices = append([]int(nil), e.Indices...)
+	return e
+}
+
+// Equal reports whether two games are in the same state. It compares
+// everything Clone copies except UI hooks, controllers, Output and Rand.
+// Cards are compared by their data, not by their Effect functions.
+func (g *Game) Equal(o *Game) bool {
+	if g.TurnCount != o.TurnCount || g.GameOver != o.GameOver || g.CurrentPhase != o.CurrentPhase ||
+		g.currentSeat() != o.currentSeat() || g.mulliganDone != o.mulliganDone ||
+		g.fatigueDamage != o.fatigueDamage || g.deckedOut != o.deckedOut || g.conceded != o.conceded {
+		return false
+	}
+	if !g.Player1.Equal(&o.Player1) || !g.Player2.Equal(&o.Player2) {
+		return false
+	}
+	if !g.Rules.equal(o.Rules) || !g.LastPlay.equal(o.LastPlay) || !fieldsEqual(g.Field, o.Field) {
+		return false
+	}
+	for i := range g.deckLists {
+		if !card.Equal(g.deckLists[i], o.deckLists[i]) {
+			return false
+		}
+	}
+	if len(g.Events) != len(o.Events) {
+		return false
+	}
+	for i := range g.Events {
+		if !g.Events[i].equal(o.Events[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// currentSeat is 0 or 1 for the player to move and -1 before the game starts.
+func (g *Game) currentSeat() int {
+	if g.CurrentPlayer == nil {
+		return -1
+	}
+	return g.seatIndex(g.CurrentPlayer)
+}
+
+func (r PlayResult) equal(o PlayResult) bool {
+	if !r.PlayerCard.Same(o.PlayerCard) || !r.OpponentCard.Same(o.OpponentCard) ||
+		r.Message != o.Message || len(r.Calculations) != len(o.Calculations) {
+		return false
+	}
+	for i, a := range r.Calculations {
+		b := o.Calculations[i]
+		if a.Kind != b.Kind || a.Base != b.Base || a.Total != b.Total || !equalSlices(a.Lines, b.Lines) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rules) equal(o Rules) bool {
+	if r.Deck.MinSize != o.Deck.MinSize || r.Deck.MaxSize != o.Deck.MaxSize ||
+		r.Deck.MaxCopies != o.Deck.MaxCopies || !equalSlices(r.Deck.Factions, o.Deck.Factions) ||
+		!equalSlices(r.Deck.Types, o.Deck.Types) || !equalSlices(r.Deck.Banned, o.Deck.Banned) {
+		return false
+	}
+	return r.Mulligan == o.Mulligan && r.MaxHandSize == o.MaxHandSize && r.EmptyHand == o.EmptyHand &&
+		r.DeckOut == o.DeckOut && r.TurnLimit == o.TurnLimit && r.SeatOrder == o.SeatOrder &&
+		r.HealthTiebreak == o.HealthTiebreak
+}
+
+func (e Event) equal(o Event) bool {
+	return e.Turn == o.Turn && e.Kind == o.Kind && e.Player == o.Player && e.Amount == o.Amount &&
+		e.Message == o.Message && equalSlices(e.Cards, o.Cards) && equalSlices(e.Indices, o.Indices)
+}
+
+func fieldsEqual(a, b *GameField) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.PlayerField.equal(&b.PlayerField) && a.OpponentField.equal(&b.OpponentField) &&
+		card.Equal(a.PlayerDeck, b.PlayerDeck) && card.Equal(a.OpponentDeck, b.OpponentDeck) &&
+		card.Equal(a.PlayerDiscard, b.PlayerDiscard) && card.Equal(a.OpponentDiscard, b.OpponentDiscard)
+}
+
+// equalSlices treats nil and empty slices as equal.
+func equalSlices[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitMix is a small random source whose whole state is one word, so a
+// game's Rand can be copied mid-stream.
+type splitMix struct {
+	state uint64
+}
+
+func (s *splitMix) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *splitMix) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	return finalize(s.state)
+}
+
+func (s *splitMix) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// Determinization is a game rebuilt from a view, with the cards the viewer
+// cannot see (the opponent's hand and both decks) still to be dealt.
+type Determinization struct {
+	base   *Game
+	own    []card.Card // the viewer's remaining deck, as a multiset
+	unseen []card.Card // the opponent's cards not seen so far
+	list   []card.Card // the opponent's deck list
+	hand   int         // the opponent's hand size
+	deck   int         // the opponent's deck size
+}
+
+// Determinize rebuilds the game the view shows. The viewer becomes Player1.
+// opponentDeck is the deck list the opponent is assumed to play; the cards
+// of it that are not on the opponent's board or in their discard pile are
+// what Sample deals into the opponent's hand and deck. A nil list leaves
+// those zones empty.
+func (v View) Determinize(opponentDeck []card.Card) *Determinization {
+	g := &Game{
+		Player1:       copyPlayer(&v.Self.snapshot),
+		Player2:       copyPlayer(&v.Opponent.snapshot),
+		CurrentPhase:  v.Phase,
+		TurnCount:     v.Turn,
+		Rules:         v.Rules,
+		mulliganDone:  [2]bool{true, true},
+		fatigueDamage: [2]int{v.Self.Fatigue, v.Opponent.Fatigue},
+		deckedOut:     [2]bool{v.Self.DeckedOut, v.Opponent.DeckedOut},
+	}
+	g.CurrentPlayer = &g.Player1
+	if !v.MyTurn {
+		g.CurrentPlayer = &g.Player2
+	}
+	g.Field = NewGameField(&g.Player1, &g.Player2)
+	for _, unit := range v.Self.Board {
+		g.Field.PlayerField.AddUnit(unit)
+	}
+	for _, unit := range v.Opponent.Board {
+		g.Field.OpponentField.AddUnit(unit)
+	}
+	g.Field.PlayerDiscard = append([]card.Card(nil), v.Self.Discard...)
+	g.Field.OpponentDiscard = append([]card.Card(nil), v.Opponent.Discard...)
+	g.Rehash()
+
+	d := &Determinization{base: g, own: v.Self.deck, list: opponentDeck,
+		hand: v.Opponent.HandSize, deck: v.Opponent.DeckSize}
+	d.unseen = append([]card.Card(nil), opponentDeck...)
+	for _, seen := range [][]card.Card{v.Opponent.Board, v.Opponent.Discard} {
+		for _, c := range seen {
+			d.unseen = removeCard(d.unseen, c)
+		}
+	}
+	return d
+}
+
+// Sample returns a new copy of the game with the hidden cards dealt at
+// random: the viewer's own deck is shuffled, and the opponent's hand and
+// deck are drawn without replacement from their unseen cards. Should the
+// opponent have more hidden cards than the deck list explains, the rest are
+// drawn from the whole list.
+func (d *Determinization) Sample(rng *rand.Rand) *Game {
+	g := d.base.Clone()
+	g.Field.PlayerDeck = shuffled(d.own, rng)
+	if len(d.list) > 0 {
+		hidden := shuffled(d.unseen, rng)
+		for len(hidden) < d.hand+d.deck {
+			hidden = append(hidden, d.list[rng.Intn(len(d.list))])
+		}
+		g.Player2.Hand = hidden[:d.hand]
+		g.Field.OpponentDeck = hidden[d.hand : d.hand+d.deck]
+	}
+	g.Rehash()
+	return g
+}
+
+// removeCard removes one copy of c from cards, if there is one.
+func removeCard(cards []card.Card, c card.Card) []card.Card {
+	for i := range cards {
+		if cards[i].Same(c) {
+			return append(cards[:i], cards[i+1:]...)
+		}
+	}
+	return cards
+}
+
+func shuffled(cards []card.Card, rng *rand.Rand) []card.Card {
+	out := append([]card.Card(nil), cards...)
+	rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
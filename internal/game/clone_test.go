@@ -0,0 +1,234 @@
+package game
+
+import (
+	"io"
+	"maps"
+	"math/rand"
+	"testing"
+
+	"GoGame/internal/card"
+	"GoGame/internal/player"
+)
+
+// newTestGame returns a seeded game a few plays in, with an item equipped,
+// a modifier active and units on both halves of the field.
+func newTestGame(t *testing.T) *Game {
+	t.Helper()
+	g, err := NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.Seed(42)
+	g.Rules.Deck.Banned = []string{"Nothing"}
+	g.DealInitialHands()
+	g.Player1.EquipItem(&player.Item{Name: "Ring of Power", Bonus: 3}, "ring")
+	g.Player2.AddModifier(player.Modifier{Source: "Blessing", Stat: player.StatArmor, Value: 2, Duration: 3})
+
+	for turn := 0; turn < 4; turn++ {
+		g.StartTurn()
+		// one unit a turn, so the hands keep cards
+		p := g.CurrentPlayer
+		for i, c := range p.Hand {
+			if c.Type == card.UnitCard {
+				if err := g.Apply(p, Action{Kind: ActionPlayCard, CardIndex: i}); err != nil {
+					t.Fatal(err)
+				}
+				break
+			}
+		}
+		g.SwitchTurn()
+	}
+	// no rule puts cards on the field yet, so place some by hand
+	g.Field.PlayerField.AddUnit(card.CreateBasicUnitCard("Statue", 2))
+	g.Field.OpponentField.AddUnit(card.CreateBasicUnitCard("Totem", 1))
+	g.Rehash()
+	return g
+}
+
+func TestCloneEqual(t *testing.T) {
+	g := newTestGame(t)
+	c := g.Clone()
+	if !g.Equal(c) || !c.Equal(g) {
+		t.Fatal("clone is not equal to the original")
+	}
+	if c.CurrentPlayer != &c.Player1 && c.CurrentPlayer != &c.Player2 {
+		t.Fatal("clone's CurrentPlayer points outside the clone")
+	}
+	if c.Field.PlayerField.Player != &c.Player1 || c.Field.OpponentField.Player != &c.Player2 {
+		t.Fatal("clone's field points outside the clone")
+	}
+	if g.Controller(&g.Player1) == nil || c.Controller(&c.Player1) != nil || c.Controller(&c.Player2) != nil {
+		t.Fatal("clone shares the original's controllers")
+	}
+}
+
+func TestCloneMutationsDoNotAffectOriginal(t *testing.T) {
+	mutations := map[string]func(c *Game){
+		"hand card": func(c *Game) {
+			c.Player1.Hand[0].Power += 10
+		},
+		"hand slice": func(c *Game) {
+			c.Player1.Hand = append(c.Player1.Hand[:0], c.Player2.Hand...)
+		},
+		"item": func(c *Game) {
+			c.Player1.Ring.Bonus = 99
+			c.Player1.Ring.Name = "Cursed"
+		},
+		"unequip": func(c *Game) {
+			c.Player1.UnequipItem("ring")
+		},
+		"modifier": func(c *Game) {
+			c.Player2.Modifiers[0].Value = 50
+		},
+		"tick modifiers": func(c *Game) {
+			c.SwitchTurn()
+		},
+		"current player": func(c *Game) {
+			c.CurrentPlayer.Health = 1
+		},
+		"field slot": func(c *Game) {
+			c.Field.PlayerField.LeftCards[0].Card.Power = 77
+		},
+		"remove unit": func(c *Game) {
+			c.Field.OpponentField.RemoveCard(0, true)
+		},
+		"deck": func(c *Game) {
+			c.Field.PlayerDeck[0].Name = "Changed"
+		},
+		"shuffle": func(c *Game) {
+			c.shuffleCards(c.Field.OpponentDeck)
+		},
+		"discard": func(c *Game) {
+			c.Field.PlayerDiscard = append(c.Field.PlayerDiscard, card.CreateBasicUnitCard("Ghost", 1))
+		},
+		"deck list": func(c *Game) {
+			c.deckLists[0][0].Power = 0
+		},
+		"event": func(c *Game) {
+			c.Events[0].Cards[0] = "Changed"
+			c.Events[0].Amount = -1
+		},
+		"calculation": func(c *Game) {
+			c.LastPlay.Calculations = append(c.LastPlay.Calculations, Calculation{Total: 1})
+		},
+		"rules": func(c *Game) {
+			c.Rules.Deck.Banned[0] = "Fireball"
+			c.Rules.TurnLimit = 1
+		},
+		"play a turn": func(c *Game) {
+			c.StartTurn()
+			p := c.CurrentPlayer
+			for i := len(p.Hand) - 1; i >= 0; i-- {
+				c.Apply(p, Action{Kind: ActionPlayCard, CardIndex: i})
+			}
+			c.SwitchTurn()
+		},
+	}
+
+	for name, mutate := range mutations {
+		t.Run(name, func(t *testing.T) {
+			g := newTestGame(t)
+			before := g.Clone()
+			c := g.Clone()
+			mutate(c)
+			if !g.Equal(before) {
+				t.Fatal("mutating the clone changed the original")
+			}
+			if g.Equal(c) {
+				t.Fatal("Equal did not notice the mutation")
+			}
+		})
+	}
+}
+
+func TestCloneKeepsRandomStream(t *testing.T) {
+	g := newTestGame(t)
+	c := g.Clone()
+
+	c.shuffleCards(c.Field.PlayerDeck)
+	g.shuffleCards(g.Field.PlayerDeck)
+	if !card.Equal(g.Field.PlayerDeck, c.Field.PlayerDeck) {
+		t.Fatal("clone and original shuffled differently")
+	}
+
+	c.shuffleCards(c.Field.PlayerDeck)
+	c.shuffleCards(c.Field.PlayerDeck)
+	want := g.Clone()
+	want.shuffleCards(want.Field.OpponentDeck)
+	g.shuffleCards(g.Field.OpponentDeck)
+	if !card.Equal(g.Field.OpponentDeck, want.Field.OpponentDeck) {
+		t.Fatal("shuffling the clone advanced the original's random source")
+	}
+}
+
+func TestEqualIgnoresSharedEffects(t *testing.T) {
+	g := newTestGame(t)
+	c := g.Clone()
+	for i := range c.Player1.Hand {
+		c.Player1.Hand[i].Effect = nil
+	}
+	if !g.Equal(c) {
+		t.Fatal("Equal compared card effects")
+	}
+}
+
+func TestEqualNilAndEmpty(t *testing.T) {
+	g := newTestGame(t)
+	c := g.Clone()
+	g.Field.PlayerDiscard = nil
+	c.Field.PlayerDiscard = []card.Card{}
+	if !g.Equal(c) {
+		t.Fatal("nil and empty discard piles compared unequal")
+	}
+}
+
+// counts returns how many copies of each card name the lists hold.
+func counts(lists ...[]card.Card) map[string]int {
+	n := make(map[string]int)
+	for _, list := range lists {
+		for _, c := range list {
+			n[c.Name]++
+		}
+	}
+	return n
+}
+
+func TestDeterminizeDealsUnseenCards(t *testing.T) {
+	g := newTestGame(t)
+	g.fatigueDamage = [2]int{2, 3}
+	g.deckedOut = [2]bool{false, true}
+	v := g.ViewFor(&g.Player1)
+	list := g.DeckList(&g.Player2)
+	d := v.Determinize(list)
+
+	for seed := int64(0); seed < 5; seed++ {
+		s := d.Sample(rand.New(rand.NewSource(seed)))
+		if !card.Equal(s.Player1.Hand, g.Player1.Hand) {
+			t.Fatal("own hand changed")
+		}
+		if !maps.Equal(counts(s.Field.PlayerDeck), counts(g.Field.PlayerDeck)) {
+			t.Fatal("own deck does not hold the cards left in it")
+		}
+		if len(s.Player2.Hand) != len(g.Player2.Hand) || len(s.Field.OpponentDeck) != len(g.Field.OpponentDeck) {
+			t.Fatal("hidden zones have the wrong sizes")
+		}
+		// every card of the list is somewhere, and no more copies
+		dealt := counts(s.Player2.Hand, s.Field.OpponentDeck, s.Field.OpponentDiscard)
+		if !maps.Equal(dealt, counts(list)) {
+			t.Fatalf("opponent's cards %v, want the deck list %v", dealt, counts(list))
+		}
+		if s.fatigueDamage != g.fatigueDamage || s.deckedOut != g.deckedOut {
+			t.Fatalf("fatigue %v and deck-out %v were not carried over", s.fatigueDamage, s.deckedOut)
+		}
+		if s.Hash() != freshHash(s) {
+			t.Fatal("sample has stale cached hashes")
+		}
+	}
+
+	first := d.Sample(rand.New(rand.NewSource(1)))
+	second := d.Sample(rand.New(rand.NewSource(1)))
+	if !first.Equal(second) {
+		t.Fatal("samples with the same seed differ")
+	}
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"GoGame/internal/player"
+)
+
+// PlayContext is passed to a card's Effect when the card is played.
+type PlayContext struct {
+	Game   *Game
+	Source *player.Player
+	Target *player.Player
+	// Card is the name of the card being played.
+	Card string
+}
+
+// DealDamage deals damage from the source to the target and credits it to
+// the card being played.
+func (ctx *PlayContext) DealDamage(base int) Calculation {
+	return ctx.Game.dealDamage(ctx.Source, ctx.Target, base, ctx.Card)
+}
+
+// CalculationKind tells whether a calculation is for damage or healing.
+type CalculationKind int
+
+const (
+	DamageCalculation CalculationKind = iota
+	HealingCalculation
+)
+
+// BonusLine is one modifier applied on top of the base amount.
+type BonusLine struct {
+	Source string `json:"source"`
+	Amount int    `json:"amount"`
+}
+
+// Calculation holds the result of the damage/heal pipeline together with
+// the modifiers that produced it, so the UI can explain the number.
+type Calculation struct {
+	Kind  CalculationKind `json:"kind"`
+	Base  int             `json:"base"`
+	Lines []BonusLine     `json:"lines,omitempty"`
+	Total int             `json:"total"`
+}
+
+// CalculateDamage applies the source player's bonuses to outgoing damage.
+func CalculateDamage(source *player.Player, base int) Calculation {
+	return calculate(DamageCalculation, source, player.StatDamage, base)
+}
+
+// CalculateHealing applies the source player's bonuses to healing.
+func CalculateHealing(source *player.Player, base int) Calculation {
+	return calculate(HealingCalculation, source, player.StatHealing, base)
+}
+
+func calculate(kind CalculationKind, source *player.Player, stat player.Stat, base int) Calculation {
+	calc := Calculation{Kind: kind, Base: base, Total: base}
+	if source != nil {
+		total, steps := source.ApplyModifiers(stat, base)
+		for _, step := range steps {
+			if step.Amount != 0 {
+				calc.Lines = append(calc.Lines, BonusLine{Source: step.Source, Amount: step.Amount})
+			}
+		}
+		calc.Total = total
+	}
+	if calc.Total < 0 {
+		calc.Total = 0
+	}
+	return calc
+}
+
+// Breakdown returns a multi-line explanation suitable for a tooltip.
+func (c Calculation) Breakdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Base %s: %d", c.kindString(), c.Base)
+	for _, line := range c.Lines {
+		fmt.Fprintf(&b, "\n%s: %+d", line.Source, line.Amount)
+	}
+	fmt.Fprintf(&b, "\nTotal: %d", c.Total)
+	return b.String()
+}
+
+func (c Calculation) kindString() string {
+	if c.Kind == HealingCalculation {
+		return "healing"
+	}
+	return "damage"
+}
+
+// DealDamage runs the damage pipeline and applies the result to target.
+// The damage is not credited to any card; card effects use
+// PlayContext.DealDamage instead.
+func (g *Game) DealDamage(source, target *player.Player, base int) Calculation {
+	return g.dealDamage(source, target, base, "")
+}
+
+func (g *Game) dealDamage(source, target *player.Player, base int, cardName string) Calculation {
+	calc := CalculateDamage(source, base)
+	target.TakeDamage(calc.Total)
+	g.recordCalculation(calc)
+	e := Event{Kind: EventDamage, Amount: calc.Total}
+	if source != nil {
+		e.Player = source.Name
+	}
+	if cardName != "" {
+		e.Cards = []string{cardName}
+	}
+	g.emit(e)
+	return calc
+}
+
+// HealPlayer runs the healing pipeline and applies the result to target.
+func (g *Game) HealPlayer(source, target *player.Player, base int) Calculation {
+	calc := CalculateHealing(source, base)
+	target.Heal(calc.Total)
+	g.recordCalculation(calc)
+	return calc
+}
+
+func (g *Game) recordCalculation(calc Calculation) {
+	g.LastPlay.Calculations = append(g.LastPlay.Calculations, calc)
+}
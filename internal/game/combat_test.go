@@ -0,0 +1,99 @@
+package game
+
+import (
+	"io"
+	"testing"
+
+	"GoGame/internal/player"
+)
+
+func TestCalculateDamageAppliesWeapon(t *testing.T) {
+	p := player.NewPlayer("Alice")
+	p.EquipItem(&player.Item{Name: "Sword", Bonus: 2}, "weapon")
+	p.EquipItem(&player.Item{Name: "Amulet", Bonus: 5}, "necklace")
+
+	calc := CalculateDamage(p, 3)
+	if calc.Total != 5 || len(calc.Lines) != 1 || calc.Lines[0] != (BonusLine{Source: "Sword", Amount: 2}) {
+		t.Fatalf("damage %+v, want 3 + 2 from the sword", calc)
+	}
+	want := "Base damage: 3\nSword: +2\nTotal: 5"
+	if got := calc.Breakdown(); got != want {
+		t.Fatalf("breakdown %q, want %q", got, want)
+	}
+
+	heal := CalculateHealing(p, 3)
+	if heal.Total != 8 || heal.Kind != HealingCalculation {
+		t.Fatalf("healing %+v, want 3 + 5 from the amulet", heal)
+	}
+	if got := heal.Breakdown(); got != "Base healing: 3\nAmulet: +5\nTotal: 8" {
+		t.Fatalf("breakdown %q", got)
+	}
+}
+
+func TestCalculateDamageNeverNegative(t *testing.T) {
+	p := player.NewPlayer("Alice")
+	p.AddModifier(player.Modifier{Source: "Weakness", Stat: player.StatDamage, Value: -10})
+
+	calc := CalculateDamage(p, 3)
+	if calc.Total != 0 || len(calc.Lines) != 1 || calc.Lines[0].Amount != -10 {
+		t.Fatalf("damage %+v, want 0 with the penalty listed", calc)
+	}
+	if calc := CalculateDamage(nil, 4); calc.Total != 4 || len(calc.Lines) != 0 {
+		t.Fatalf("damage without a source %+v, want the base", calc)
+	}
+}
+
+func TestFireballGoesThroughThePipeline(t *testing.T) {
+	g, err := NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.Player1.EquipItem(&player.Item{Name: "Sword", Bonus: 2}, "weapon")
+	g.StartTurn()
+	fireball, _ := FindCard("Fireball")
+	g.Player1.Hand = append(g.Player1.Hand, fireball)
+
+	g.PlayCard(&g.Player1, len(g.Player1.Hand)-1)
+	if g.Player1.Health != 100 || g.Player2.Health != 95 {
+		t.Fatalf("health %d/%d, want the opponent to take 3 + 2", g.Player1.Health, g.Player2.Health)
+	}
+	calcs := g.LastPlay.Calculations
+	if len(calcs) != 1 || calcs[0].Total != 5 || calcs[0].Base != 3 {
+		t.Fatalf("last play calculations %+v", calcs)
+	}
+	if n := len(g.Field.PlayerDiscard); n != 1 {
+		t.Fatalf("%d cards discarded, want the fireball", n)
+	}
+}
+
+func TestDamageIsCreditedToItsCard(t *testing.T) {
+	g, err := NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.StartTurn()
+	fireball, _ := FindCard("Fireball")
+	g.Player1.Hand = append(g.Player1.Hand, fireball)
+	g.PlayCard(&g.Player1, len(g.Player1.Hand)-1)
+
+	// damage from outside a card effect, after the fireball was played
+	g.DealDamage(nil, &g.Player2, 1)
+
+	var damage []Event
+	for _, e := range g.Events {
+		if e.Kind == EventDamage {
+			damage = append(damage, e)
+		}
+	}
+	if len(damage) != 2 {
+		t.Fatalf("damage events %+v", damage)
+	}
+	if e := damage[0]; e.Player != "Player 1" || len(e.Cards) != 1 || e.Cards[0] != "Fireball" || e.Amount != 3 {
+		t.Fatalf("fireball damage %+v", e)
+	}
+	if e := damage[1]; e.Player != "" || len(e.Cards) != 0 || e.Amount != 1 {
+		t.Fatalf("sourceless damage %+v, want no player or card", e)
+	}
+}
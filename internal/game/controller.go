@@ -0,0 +1,321 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"GoGame/internal/player"
+)
+
+// ActionKind identifies what a player wants to do.
+type ActionKind int
+
+const (
+	ActionPlayCard ActionKind = iota
+	ActionEndTurn
+	ActionConcede // gives up the game; never listed by LegalActions
+)
+
+// Action is a single decision made by a controller.
+type Action struct {
+	Kind      ActionKind
+	CardIndex int
+}
+
+func (a Action) String() string {
+	switch a.Kind {
+	case ActionEndTurn:
+		return "end turn"
+	case ActionConcede:
+		return "concede"
+	}
+	return fmt.Sprintf("play card %d", a.CardIndex)
+}
+
+// Controller decides the actions for one seat. It only ever sees a View of
+// the game, never the Game itself.
+type Controller interface {
+	// Mulligan returns the hand positions to shuffle back and redraw.
+	Mulligan(v View) []int
+	// NextAction returns the next action for the current turn. Returning
+	// ActionEndTurn passes the turn to the opponent.
+	NextAction(v View) Action
+}
+
+// ActionObserver is implemented by controllers that want to know whether
+// their actions were accepted.
+type ActionObserver interface {
+	ActionResult(a Action, err error)
+}
+
+// maxActionsPerTurn stops a controller that never ends its turn.
+const maxActionsPerTurn = 50
+
+var (
+	ErrNotYourTurn = errors.New("it is not your turn")
+	ErrWrongPhase  = errors.New("cards can only be played in the play phase")
+	ErrNoSuchCard  = errors.New("no card at that position")
+	ErrNoMana      = errors.New("not enough mana")
+)
+
+// SetController assigns a controller to the player's seat.
+func (g *Game) SetController(p *player.Player, c Controller) {
+	g.controllers[g.seatIndex(p)] = c
+}
+
+// Controller returns the controller of the player's seat.
+func (g *Game) Controller(p *player.Player) Controller {
+	return g.controllers[g.seatIndex(p)]
+}
+
+// Apply validates an action for p and carries it out.
+func (g *Game) Apply(p *player.Player, a Action) error {
+	if g.CurrentPlayer != p {
+		return ErrNotYourTurn
+	}
+	switch a.Kind {
+	case ActionEndTurn:
+		return nil
+	case ActionConcede:
+		g.Concede(p)
+		return nil
+	}
+	if g.CurrentPhase != PlayPhase {
+		return ErrWrongPhase
+	}
+	if a.CardIndex < 0 || a.CardIndex >= len(p.Hand) {
+		return ErrNoSuchCard
+	}
+	if p.Hand[a.CardIndex].Cost > p.Mana {
+		return ErrNoMana
+	}
+	g.PlayCard(p, a.CardIndex)
+	return nil
+}
+
+// playTurn asks the current player's controller for actions until it ends
+// the turn or the game is over.
+func (g *Game) playTurn(p *player.Player, epoch int64) {
+	c := g.Controller(p)
+	for i := 0; i < maxActionsPerTurn && !g.CheckGameOver(); i++ {
+		action := c.NextAction(g.ViewFor(p))
+		if g.stale(epoch) {
+			return
+		}
+		err := g.Apply(p, action)
+		if o, ok := c.(ActionObserver); ok {
+			o.ActionResult(action, err)
+		}
+		if action.Kind == ActionEndTurn {
+			return
+		}
+		if err == nil && g.UIUpdate != nil {
+			g.UIUpdate()
+		}
+	}
+}
+
+// HumanController is driven by the UI: it waits for actions submitted with
+// Submit and SubmitMulligan. Submissions made while the game is not waiting
+// for the player are dropped, so a double click cannot carry over into the
+// next turn.
+type HumanController struct {
+	mu                         sync.Mutex
+	actions                    chan Action
+	mulligan                   chan []int
+	results                    chan error
+	done                       chan struct{} // closed by Reset
+	wantsAction, wantsMulligan bool
+}
+
+// ErrNotWaiting is returned by Submit when the game is not waiting for the
+// player.
+var ErrNotWaiting = errors.New("the game is not waiting for you")
+
+// NewHumanController creates a controller for a player using the UI.
+func NewHumanController() *HumanController {
+	h := &HumanController{}
+	h.Reset()
+	return h
+}
+
+// Reset releases any game loop and UI call still waiting on the controller,
+// so a new game can start without the old loop picking up its actions.
+func (h *HumanController) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.done != nil {
+		close(h.done)
+	}
+	h.actions = make(chan Action, 1)
+	h.mulligan = make(chan []int, 1)
+	h.results = make(chan error, 1)
+	h.done = make(chan struct{})
+	h.wantsAction, h.wantsMulligan = false, false
+}
+
+// Submit hands an action to the game loop and waits until it has been applied.
+func (h *HumanController) Submit(a Action) error {
+	h.mu.Lock()
+	if !h.wantsAction {
+		h.mu.Unlock()
+		return ErrNotWaiting
+	}
+	h.wantsAction = false
+	h.actions <- a
+	results, done := h.results, h.done
+	h.mu.Unlock()
+
+	select {
+	case err := <-results:
+		return err
+	case <-done:
+		return ErrNotWaiting
+	}
+}
+
+// SubmitMulligan hands the mulligan choice to the game loop.
+func (h *HumanController) SubmitMulligan(indices []int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.wantsMulligan {
+		return ErrNotWaiting
+	}
+	h.wantsMulligan = false
+	h.mulligan <- indices
+	return nil
+}
+
+// Mulligan waits for SubmitMulligan. After Reset it keeps the hand.
+func (h *HumanController) Mulligan(v View) []int {
+	h.mu.Lock()
+	h.wantsMulligan = true
+	mulligan, done := h.mulligan, h.done
+	h.mu.Unlock()
+
+	select {
+	case indices := <-mulligan:
+		return indices
+	case <-done:
+		return nil
+	}
+}
+
+// NextAction waits for Submit. After Reset it ends the turn; the game loop
+// has been stopped by then and does not apply it.
+func (h *HumanController) NextAction(v View) Action {
+	h.mu.Lock()
+	h.wantsAction = true
+	actions, done := h.actions, h.done
+	h.mu.Unlock()
+
+	select {
+	case a := <-actions:
+		return a
+	case <-done:
+		return Action{Kind: ActionEndTurn}
+	}
+}
+
+func (h *HumanController) ActionResult(a Action, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	select {
+	case h.results <- err:
+	default:
+	}
+}
+
+// RandomController plays one random card per turn, then ends it.
+type RandomController struct {
+	rng        *rand.Rand
+	playedTurn int
+}
+
+// NewRandomController creates a random player. A nil rng uses the global source.
+func NewRandomController(rng *rand.Rand) *RandomController {
+	return &RandomController{rng: rng, playedTurn: -1}
+}
+
+func (r *RandomController) intn(n int) int {
+	if r.rng == nil {
+		return rand.Intn(n)
+	}
+	return r.rng.Intn(n)
+}
+
+// Reset forgets the turn played in the previous game.
+func (r *RandomController) Reset() {
+	r.playedTurn = -1
+}
+
+func (r *RandomController) Mulligan(v View) []int {
+	return AIMulligan(v)
+}
+
+func (r *RandomController) NextAction(v View) Action {
+	var plays []Action
+	for _, a := range v.LegalActions() {
+		if a.Kind == ActionPlayCard {
+			plays = append(plays, a)
+		}
+	}
+	if r.playedTurn == v.Turn || len(plays) == 0 {
+		return Action{Kind: ActionEndTurn}
+	}
+	r.playedTurn = v.Turn
+	return plays[r.intn(len(plays))]
+}
+
+// ScriptedController replays a fixed list of actions. When the script runs
+// out it ends every turn.
+type ScriptedController struct {
+	MulliganIndices []int
+	Actions         []Action
+	next            int
+}
+
+func (s *ScriptedController) Mulligan(v View) []int {
+	return s.MulliganIndices
+}
+
+func (s *ScriptedController) NextAction(v View) Action {
+	if s.next >= len(s.Actions) {
+		return Action{Kind: ActionEndTurn}
+	}
+	a := s.Actions[s.next]
+	s.next++
+	return a
+}
+
+// WithDelay wraps a controller so that each decision takes at least d,
+// which makes computer players readable on screen.
+func WithDelay(c Controller, d time.Duration) Controller {
+	return delayedController{Controller: c, delay: d}
+}
+
+type delayedController struct {
+	Controller
+	delay time.Duration
+}
+
+func (d delayedController) NextAction(v View) Action {
+	time.Sleep(d.delay)
+	return d.Controller.NextAction(v)
+}
+
+// ActionResult forwards the outcome to the wrapped controller if it wants it.
+func (d delayedController) ActionResult(a Action, err error) {
+	if o, ok := d.Controller.(ActionObserver); ok {
+		o.ActionResult(a, err)
+	}
+}
+
+func (d delayedController) Reset() {
+	if r, ok := d.Controller.(interface{ Reset() }); ok {
+		r.Reset()
+	}
+}
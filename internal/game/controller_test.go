@@ -0,0 +1,182 @@
+package game
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	"GoGame/internal/card"
+)
+
+// waitFor polls cond until it holds or a second has passed.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (h *HumanController) waiting() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.wantsAction
+}
+
+func TestHumanSubmitOutsideTurnIsDropped(t *testing.T) {
+	h := NewHumanController()
+	if err := h.Submit(Action{Kind: ActionEndTurn}); !errors.Is(err, ErrNotWaiting) {
+		t.Fatalf("submit before the turn: %v, want ErrNotWaiting", err)
+	}
+	if err := h.SubmitMulligan(nil); !errors.Is(err, ErrNotWaiting) {
+		t.Fatalf("mulligan outside the mulligan: %v, want ErrNotWaiting", err)
+	}
+
+	got := make(chan Action)
+	go func() { got <- h.NextAction(View{}) }()
+	waitFor(t, "the turn", h.waiting)
+
+	submitted := make(chan error)
+	go func() { submitted <- h.Submit(Action{Kind: ActionEndTurn}) }()
+	if a := <-got; a.Kind != ActionEndTurn {
+		t.Fatalf("got %v", a)
+	}
+	// the second click of a double click
+	if err := h.Submit(Action{Kind: ActionEndTurn}); !errors.Is(err, ErrNotWaiting) {
+		t.Fatalf("second end turn: %v, want ErrNotWaiting", err)
+	}
+	h.ActionResult(Action{Kind: ActionEndTurn}, nil)
+	if err := <-submitted; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHumanResetReleasesWaiters(t *testing.T) {
+	h := NewHumanController()
+	got := make(chan Action)
+	go func() { got <- h.NextAction(View{}) }()
+	waitFor(t, "the turn", h.waiting)
+
+	submitted := make(chan error)
+	go func() { submitted <- h.Submit(Action{Kind: ActionPlayCard}) }()
+	<-got
+	h.Reset()
+	select {
+	case err := <-submitted:
+		if !errors.Is(err, ErrNotWaiting) {
+			t.Fatalf("submit across a reset: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Submit still blocked after Reset")
+	}
+
+	go func() { got <- h.NextAction(View{}) }()
+	waitFor(t, "the turn", h.waiting)
+	h.Reset()
+	select {
+	case <-got:
+	case <-time.After(time.Second):
+		t.Fatal("NextAction still blocked after Reset")
+	}
+}
+
+func TestResetStopsTheRunningLoop(t *testing.T) {
+	g, err := NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	human := NewHumanController()
+	g.SetController(&g.Player1, human)
+	g.SetController(&g.Player2, NewRandomController(rand.New(rand.NewSource(1))))
+	if err := g.Mulligan(&g.Player1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		g.GameLoop()
+		close(stopped)
+	}()
+	waitFor(t, "the human's turn", human.waiting)
+	g.Reset()
+	dealt := len(g.Events)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("old game loop is still running")
+	}
+	if g.TurnCount != 0 || g.CurrentPhase != DrawPhase || len(g.Events) != dealt {
+		t.Fatalf("old loop changed the new game: turn %d, phase %v, %d events", g.TurnCount, g.CurrentPhase, len(g.Events))
+	}
+}
+
+type observer struct {
+	ScriptedController
+	results []error
+}
+
+func (o *observer) ActionResult(a Action, err error) {
+	o.results = append(o.results, err)
+}
+
+func TestDelayedControllerForwards(t *testing.T) {
+	o := &observer{}
+	d := WithDelay(o, 0)
+	d.(ActionObserver).ActionResult(Action{}, ErrNoSuchCard)
+	if len(o.results) != 1 || o.results[0] != ErrNoSuchCard {
+		t.Fatalf("results %v", o.results)
+	}
+
+	r := NewRandomController(rand.New(rand.NewSource(1)))
+	v := View{MyTurn: true, Phase: PlayPhase, Turn: 3}
+	v.Self.Mana = 10
+	v.Self.Hand = InitializeDeck()[:2]
+	if a := r.NextAction(v); a.Kind != ActionPlayCard {
+		t.Fatalf("first action %v, want a play", a)
+	}
+	WithDelay(r, 0).(interface{ Reset() }).Reset()
+	if a := r.NextAction(v); a.Kind != ActionPlayCard {
+		t.Fatalf("after Reset %v, want a play on the same turn number", a)
+	}
+}
+
+func TestRandomControllerWithoutLegalActionsEndsTurn(t *testing.T) {
+	r := NewRandomController(rand.New(rand.NewSource(1)))
+	v := View{Phase: DrawPhase, Turn: 1}
+	v.Self.Hand = []card.Card{card.CreateBasicUnitCard("Soldier", 1)}
+	if a := r.NextAction(v); a.Kind != ActionEndTurn {
+		t.Fatalf("random controller chose %+v outside its turn, want end turn", a)
+	}
+
+	v.MyTurn, v.Phase = true, PlayPhase
+	if a := r.NextAction(v); a.Kind != ActionEndTurn {
+		t.Fatalf("random controller chose %+v with no mana, want end turn", a)
+	}
+	v.Self.Mana = 1
+	if a := r.NextAction(v); a.Kind != ActionPlayCard || a.CardIndex != 0 {
+		t.Fatalf("random controller chose %+v, want the soldier", a)
+	}
+}
+
+func TestApplyRejectsUnaffordableCards(t *testing.T) {
+	g, err := NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.CurrentPhase = PlayPhase
+	g.Player1.Hand = []card.Card{card.CreateBasicUnitCard("Titan", 9)}
+	g.Player1.Mana = 8
+	if err := g.Apply(&g.Player1, Action{Kind: ActionPlayCard}); err != ErrNoMana {
+		t.Fatalf("Apply returned %v, want ErrNoMana", err)
+	}
+	if len(g.Player1.Hand) != 1 || g.Player1.Mana != 8 {
+		t.Fatalf("hand %d mana %d after a rejected play", len(g.Player1.Hand), g.Player1.Mana)
+	}
+}
@@ -0,0 +1,159 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"GoGame/internal/card"
+)
+
+// DeckRules describes what a deck may contain.
+type DeckRules struct {
+	MinSize   int `json:"min_size"`
+	MaxSize   int `json:"max_size"`   // 0 means no upper limit
+	MaxCopies int `json:"max_copies"` // 0 means no copy limit
+
+	// Factions lists the factions a deck may draw from. Neutral cards are
+	// always allowed; an empty list allows every faction.
+	Factions []string `json:"factions,omitempty"`
+	// Types lists the card types a deck may contain; empty allows all.
+	Types []card.CardType `json:"types,omitempty"`
+	// Banned lists card names that may not appear in a deck at all.
+	Banned []string `json:"banned,omitempty"`
+}
+
+// DefaultDeckRules returns the standard deck construction rules.
+func DefaultDeckRules() DeckRules {
+	return DeckRules{
+		MinSize:   10,
+		MaxSize:   40,
+		MaxCopies: 3,
+	}
+}
+
+// DeckViolation is one broken deck construction rule.
+type DeckViolation struct {
+	Rule   string
+	Card   string // empty when the violation concerns the whole deck
+	Reason string
+}
+
+func (v DeckViolation) String() string {
+	if v.Card == "" {
+		return fmt.Sprintf("%s: %s", v.Rule, v.Reason)
+	}
+	return fmt.Sprintf("%s: %s: %s", v.Rule, v.Card, v.Reason)
+}
+
+// DeckError is returned when a deck breaks the rules.
+type DeckError struct {
+	Owner      string
+	Violations []DeckViolation
+}
+
+func (e *DeckError) Error() string {
+	lines := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		lines[i] = v.String()
+	}
+	return fmt.Sprintf("invalid deck for %s: %s", e.Owner, strings.Join(lines, "; "))
+}
+
+// ValidateDeck checks a deck against the rules and returns every violation.
+func ValidateDeck(deck []card.Card, rules DeckRules) []DeckViolation {
+	var violations []DeckViolation
+
+	if len(deck) < rules.MinSize {
+		violations = append(violations, DeckViolation{
+			Rule:   "min-size",
+			Reason: fmt.Sprintf("deck has %d cards, at least %d required", len(deck), rules.MinSize),
+		})
+	}
+	if rules.MaxSize > 0 && len(deck) > rules.MaxSize {
+		violations = append(violations, DeckViolation{
+			Rule:   "max-size",
+			Reason: fmt.Sprintf("deck has %d cards, at most %d allowed", len(deck), rules.MaxSize),
+		})
+	}
+
+	counts := make(map[string]int)
+	for _, c := range deck {
+		counts[c.Name]++
+	}
+	checked := make(map[string]bool)
+	for _, c := range deck {
+		if checked[c.Name] {
+			continue
+		}
+		checked[c.Name] = true
+
+		if rules.MaxCopies > 0 && counts[c.Name] > rules.MaxCopies {
+			violations = append(violations, DeckViolation{
+				Rule:   "copy-limit",
+				Card:   c.Name,
+				Reason: fmt.Sprintf("%d copies, at most %d allowed", counts[c.Name], rules.MaxCopies),
+			})
+		}
+		if containsString(rules.Banned, c.Name) {
+			violations = append(violations, DeckViolation{
+				Rule:   "banned",
+				Card:   c.Name,
+				Reason: "card is banned",
+			})
+		}
+		if c.Faction != "" && len(rules.Factions) > 0 && !containsString(rules.Factions, c.Faction) {
+			violations = append(violations, DeckViolation{
+				Rule:   "faction",
+				Card:   c.Name,
+				Reason: fmt.Sprintf("faction %q is not allowed", c.Faction),
+			})
+		}
+		if len(rules.Types) > 0 && !containsType(rules.Types, c.Type) {
+			violations = append(violations, DeckViolation{
+				Rule:   "card-type",
+				Card:   c.Name,
+				Reason: "card type is not allowed",
+			})
+		}
+	}
+
+	return violations
+}
+
+// FindCard looks a card up by name among the cards InitializeDeck knows about.
+func FindCard(name string) (card.Card, bool) {
+	for _, c := range InitializeDeck() {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return card.Card{}, false
+}
+
+// FindCardByID looks a card up by its ID among the cards InitializeDeck knows about.
+func FindCardByID(id int) (card.Card, bool) {
+	for _, c := range InitializeDeck() {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return card.Card{}, false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsType(list []card.CardType, t card.CardType) bool {
+	for _, item := range list {
+		if item == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"GoGame/internal/card"
+)
+
+func TestValidateDeck(t *testing.T) {
+	unit := func(name string, n int) []card.Card {
+		cards := make([]card.Card, n)
+		for i := range cards {
+			cards[i] = card.CreateBasicUnitCard(name, 1)
+		}
+		return cards
+	}
+	join := func(parts ...[]card.Card) []card.Card {
+		var deck []card.Card
+		for _, p := range parts {
+			deck = append(deck, p...)
+		}
+		return deck
+	}
+	rules := DeckRules{MinSize: 4, MaxSize: 6, MaxCopies: 2}
+
+	tests := []struct {
+		name string
+		deck []card.Card
+		want []string
+	}{
+		{"valid", join(unit("A", 2), unit("B", 2)), nil},
+		{"exactly the maximum", join(unit("A", 2), unit("B", 2), unit("C", 2)), nil},
+		{"too small", unit("A", 2), []string{"min-size: deck has 2 cards, at least 4 required"}},
+		{"empty", nil, []string{"min-size: deck has 0 cards, at least 4 required"}},
+		{"too large", join(unit("A", 2), unit("B", 2), unit("C", 2), unit("D", 1)),
+			[]string{"max-size: deck has 7 cards, at most 6 allowed"}},
+		{"too many copies", join(unit("A", 3), unit("B", 1)),
+			[]string{"copy-limit: A: 3 copies, at most 2 allowed"}},
+		{"every violation", join(unit("A", 4), unit("B", 3)), []string{
+			"max-size: deck has 7 cards, at most 6 allowed",
+			"copy-limit: A: 4 copies, at most 2 allowed",
+			"copy-limit: B: 3 copies, at most 2 allowed",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range ValidateDeck(tt.deck, rules) {
+				got = append(got, v.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("violations %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDeckWithoutLimits(t *testing.T) {
+	deck := make([]card.Card, 100)
+	for i := range deck {
+		deck[i] = card.CreateBasicUnitCard("A", 1)
+	}
+	if v := ValidateDeck(deck, DeckRules{}); len(v) != 0 {
+		t.Fatalf("zero rules reported %v", v)
+	}
+}
+
+func TestDeckErrorListsEveryViolation(t *testing.T) {
+	deck := make([]card.Card, 5)
+	for i := range deck {
+		deck[i] = card.CreateBasicUnitCard("Soldier", 1)
+	}
+	_, err := NewGameWithDecks(deck, InitializeDeck(), DefaultRules())
+	want := "invalid deck for Player 1: min-size: deck has 5 cards, at least 10 required; copy-limit: Soldier: 5 copies, at most 3 allowed"
+	if err == nil || err.Error() != want {
+		t.Fatalf("error %v, want %q", err, want)
+	}
+}
+
+func TestFindCardRejectsUnknownCards(t *testing.T) {
+	if c, ok := FindCard("fireball"); !ok || c.Name != "Fireball" {
+		t.Fatalf("FindCard(fireball) = %v, %v", c.Name, ok)
+	}
+	if _, ok := FindCard("Goblin"); ok {
+		t.Fatal("found a card the catalogue does not have")
+	}
+	if _, ok := FindCardByID(-1); ok {
+		t.Fatal("found a card with an unknown id")
+	}
+}
@@ -0,0 +1,71 @@
+package game
+
+import "fmt"
+
+// EventKind identifies what happened in a game event.
+type EventKind int
+
+const (
+	EventMulligan EventKind = iota
+	EventDraw
+	EventBurn
+	EventFatigue
+	EventDeckOut
+	EventPlay
+	EventDamage // Player dealt Amount damage with the card in Cards
+	EventConcede
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventMulligan:
+		return "mulligan"
+	case EventDraw:
+		return "draw"
+	case EventBurn:
+		return "burn"
+	case EventFatigue:
+		return "fatigue"
+	case EventDeckOut:
+		return "deck out"
+	case EventPlay:
+		return "play"
+	case EventDamage:
+		return "damage"
+	case EventConcede:
+		return "concede"
+	default:
+		return "unknown"
+	}
+}
+
+func (k EventKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *EventKind) UnmarshalText(text []byte) error {
+	for kind := EventMulligan; kind <= EventConcede; kind++ {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown event kind %q", text)
+}
+
+// Event is one entry of the game record. The record is kept in order in
+// Game.Events so that a finished game can be replayed or analysed.
+type Event struct {
+	Turn    int       `json:"turn"`
+	Kind    EventKind `json:"kind"`
+	Player  string    `json:"player"`
+	Cards   []string  `json:"cards,omitempty"`   // names of the cards involved
+	Indices []int     `json:"indices,omitempty"` // hand positions chosen by the player, if any
+	Amount  int       `json:"amount,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+func (g *Game) emit(e Event) {
+	e.Turn = g.TurnCount
+	g.Events = append(g.Events, e)
+}
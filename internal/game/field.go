@@ -84,4 +84,77 @@ func (pf *PlayerField) RemoveCard(position int, isLeft bool) *card.Card {
 	targetSlots[position].Card = nil
 	targetSlots[position].IsOccupied = false
 	return card
+}
+
+// AddUnit ставит юнита в первый свободный слот: сначала слева, затем справа
+func (pf *PlayerField) AddUnit(unit card.Card) bool {
+	for _, isLeft := range []bool{true, false} {
+		for position := 0; position < 3; position++ {
+			c := unit
+			if pf.PlaceCard(&c, position, isLeft) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Units возвращает карты всех юнитов на этой половине поля
+func (pf *PlayerField) Units() []card.Card {
+	var units []card.Card
+	for _, slots := range [][3]CardSlot{pf.LeftCards, pf.RightCards} {
+		for _, slot := range slots {
+			if slot.IsOccupied {
+				units = append(units, *slot.Card)
+			}
+		}
+	}
+	return units
+}
+
+// clone создаёт независимую копию поля, привязанную к новым игрокам
+func (gf *GameField) clone(player, opponent *player.Player) *GameField {
+	return &GameField{
+		PlayerField:     gf.PlayerField.clone(player),
+		OpponentField:   gf.OpponentField.clone(opponent),
+		PlayerDeck:      append([]card.Card(nil), gf.PlayerDeck...),
+		OpponentDeck:    append([]card.Card(nil), gf.OpponentDeck...),
+		PlayerDiscard:   append([]card.Card(nil), gf.PlayerDiscard...),
+		OpponentDiscard: append([]card.Card(nil), gf.OpponentDiscard...),
+	}
+}
+
+func (pf PlayerField) clone(owner *player.Player) PlayerField {
+	pf.Player = owner
+	for i := range pf.LeftCards {
+		pf.LeftCards[i] = pf.LeftCards[i].clone()
+		pf.RightCards[i] = pf.RightCards[i].clone()
+	}
+	pf.PlayerCard = pf.PlayerCard.clone()
+	return pf
+}
+
+func (s CardSlot) clone() CardSlot {
+	if s.Card != nil {
+		c := *s.Card
+		s.Card = &c
+	}
+	return s
+}
+
+// equal сравнивает карты в слотах двух половин поля
+func (pf *PlayerField) equal(o *PlayerField) bool {
+	for i := range pf.LeftCards {
+		if !pf.LeftCards[i].equal(o.LeftCards[i]) || !pf.RightCards[i].equal(o.RightCards[i]) {
+			return false
+		}
+	}
+	return pf.PlayerCard.equal(o.PlayerCard)
+}
+
+func (s CardSlot) equal(o CardSlot) bool {
+	if s.IsOccupied != o.IsOccupied || (s.Card == nil) != (o.Card == nil) {
+		return false
+	}
+	return s.Card == nil || s.Card.Same(*o.Card)
 }
\ No newline at end of file
@@ -2,7 +2,10 @@ package game
 
 import (
 	"fmt"
+	"io"
 	"math/rand"
+	"os"
+	"sync/atomic"
 	"time"
 
 	"GoGame/internal/card"
@@ -18,8 +21,38 @@ const (
 	DrawPhase GamePhase = iota
 	PlayPhase
 	EndPhase
+	MulliganPhase
 )
 
+func (p GamePhase) String() string {
+	switch p {
+	case DrawPhase:
+		return "draw"
+	case PlayPhase:
+		return "play"
+	case EndPhase:
+		return "end"
+	case MulliganPhase:
+		return "mulligan"
+	default:
+		return "unknown"
+	}
+}
+
+func (p GamePhase) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *GamePhase) UnmarshalText(text []byte) error {
+	for _, phase := range []GamePhase{DrawPhase, PlayPhase, EndPhase, MulliganPhase} {
+		if phase.String() == string(text) {
+			*p = phase
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown phase %q", text)
+}
+
 type Game struct {
 	Player1       player.Player
 	Player2       player.Player
@@ -27,45 +60,130 @@ type Game struct {
 	ScoreLabel    *widget.Label
 	window        fyne.Window
 	LastPlay      PlayResult
-	Deck          []card.Card
-	DiscardPile   []card.Card
+	Field         *GameField
 	TurnCount     int
 	GameOver      bool
 	UIUpdate      func()
 	CurrentPhase  GamePhase
-	EndTurn       chan bool
+	Rules         Rules
+	Events        []Event
+	Output        io.Writer  // game log; nil writes to standard output
+	Rand          *rand.Rand // shuffles; nil uses the global source
+
+	controllers   [2]Controller
+	deckLists     [2][]card.Card
+	mulliganDone  [2]bool
+	fatigueDamage [2]int
+	deckedOut     [2]bool
+	conceded      [2]bool
+	source        *splitMix  // generator behind seeded, copied by Clone
+	seeded        *rand.Rand // the Rand created by Seed
+	zones         zoneHashes
+	epoch         atomic.Int64 // bumped by Reset to stop a running GameLoop
+}
+
+// EndReason tells why a game ended.
+type EndReason int
+
+const (
+	EndNone EndReason = iota
+	EndHealth
+	EndEmptyHand
+	EndDeckOut
+	EndTurnLimit
+	EndConcede
+)
+
+func (r EndReason) String() string {
+	switch r {
+	case EndHealth:
+		return "health"
+	case EndEmptyHand:
+		return "empty hand"
+	case EndDeckOut:
+		return "deck out"
+	case EndTurnLimit:
+		return "turn limit"
+	case EndConcede:
+		return "concede"
+	default:
+		return "not over"
+	}
+}
+
+// Result describes how a game ended. Winner is nil for a tie.
+type Result struct {
+	Winner *player.Player
+	Reason EndReason
 }
 
 type PlayResult struct {
-	PlayerCard   card.Card
-	OpponentCard card.Card
-	Message      string
+	PlayerCard   card.Card     `json:"player_card"`
+	OpponentCard card.Card     `json:"opponent_card"`
+	Message      string        `json:"message"`
+	Calculations []Calculation `json:"calculations,omitempty"`
 }
 
-func NewGame() *Game {
+// NewGame creates a game with the default rules in which both players
+// use the default deck.
+func NewGame() (*Game, error) {
+	return NewGameWithDecks(InitializeDeck(), InitializeDeck(), DefaultRules())
+}
+
+// NewGameWithDecks creates a game in which each player draws from their
+// own deck. Both decks are checked against rules.Deck first. The lists are
+// copied and shuffled, so they can be reused.
+func NewGameWithDecks(deck1, deck2 []card.Card, rules Rules) (*Game, error) {
 	player1, player2 := initializePlayers()
+	if violations := ValidateDeck(deck1, rules.Deck); len(violations) > 0 {
+		return nil, &DeckError{Owner: player1.Name, Violations: violations}
+	}
+	if violations := ValidateDeck(deck2, rules.Deck); len(violations) > 0 {
+		return nil, &DeckError{Owner: player2.Name, Violations: violations}
+	}
+
 	game := &Game{
 		Player1:      player1,
 		Player2:      player2,
-		Deck:         InitializeDeck(),
 		CurrentPhase: DrawPhase,
-		EndTurn:      make(chan bool),
+		Rules:        rules,
+		deckLists:    [2][]card.Card{deck1, deck2},
+	}
+	// By default Player 1 is the human at the UI and Player 2 plays randomly
+	game.controllers = [2]Controller{
+		NewHumanController(),
+		WithDelay(NewRandomController(nil), time.Second),
 	}
 	game.CurrentPlayer = &game.Player1
-	return game
+	game.Field = NewGameField(&game.Player1, &game.Player2)
+	game.setupDecks()
+	return game, nil
 }
 
+// Reset starts a new game in place. A GameLoop still running for the old
+// game stops at its next decision.
 func (g *Game) Reset() {
+	g.epoch.Add(1)
 	player1, player2 := initializePlayers()
 	g.Player1 = player1
 	g.Player2 = player2
 	g.CurrentPlayer = &g.Player1
-	g.Deck = InitializeDeck()
-	g.DiscardPile = []card.Card{}
+	g.Field = NewGameField(&g.Player1, &g.Player2)
+	g.Rehash()
+	g.setupDecks()
 	g.TurnCount = 0
 	g.GameOver = false
 	g.CurrentPhase = DrawPhase
-	g.EndTurn = make(chan bool)
+	g.Events = nil
+	for _, c := range g.controllers {
+		if r, ok := c.(interface{ Reset() }); ok {
+			r.Reset()
+		}
+	}
+	g.mulliganDone = [2]bool{}
+	g.fatigueDamage = [2]int{}
+	g.deckedOut = [2]bool{}
+	g.conceded = [2]bool{}
 	g.DealInitialHands()
 }
 
@@ -77,40 +195,60 @@ func (g *Game) GetWindow() fyne.Window {
 	return g.window
 }
 
+func (g *Game) logf(format string, args ...interface{}) {
+	out := g.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	fmt.Fprintf(out, format, args...)
+}
+
 func (g *Game) UpdateScore() {
+	if g.ScoreLabel == nil {
+		return
+	}
 	g.ScoreLabel.SetText(fmt.Sprintf("Score - %s: %d, %s: %d", g.Player1.Name, g.Player1.Score, g.Player2.Name, g.Player2.Score))
 }
 
 func (g *Game) PlayCard(player *player.Player, cardIndex int) {
-	if cardIndex >= len(player.Hand) {
+	if cardIndex < 0 || cardIndex >= len(player.Hand) {
 		return
 	}
 
 	playerCard := player.Hand[cardIndex]
+	if !player.UseMana(playerCard.Cost) {
+		return
+	}
 	player.Hand = append(player.Hand[:cardIndex], player.Hand[cardIndex+1:]...)
-
-	// Play the card
-	playerCard.Play(player)
-
-	message := fmt.Sprintf("%s played %s", player.Name, playerCard.GetInfo())
-	g.UpdateScore()
+	g.take(player, zoneHand, playerCard)
 
 	g.LastPlay = PlayResult{
 		PlayerCard: playerCard,
-		Message:    message,
 	}
+	g.emit(Event{Kind: EventPlay, Player: player.Name, Cards: []string{playerCard.Name}})
+
+	// Play the card
+	opponent := g.Opponent(player)
+	g.logf("Playing card: %s\n", playerCard.Name)
+	if playerCard.Effect != nil {
+		playerCard.Effect(&PlayContext{Game: g, Source: player, Target: opponent, Card: playerCard.Name})
+	}
+
+	g.LastPlay.Message = fmt.Sprintf("%s played %s", player.Name, playerCard.GetInfo())
+	g.UpdateScore()
 
-	// Add played card to discard pile
-	g.DiscardPile = append(g.DiscardPile, playerCard)
+	// Add played card to the owner's discard pile
+	discard := g.DiscardOf(player)
+	*discard = append(*discard, playerCard)
+	g.put(player, zoneDiscard, playerCard)
 }
 
-func (g *Game) PlayRandomCard(player *player.Player) {
-	if len(player.Hand) == 0 {
-		return
+// Opponent returns the other player.
+func (g *Game) Opponent(p *player.Player) *player.Player {
+	if p == &g.Player1 {
+		return &g.Player2
 	}
-
-	cardIndex := rand.Intn(len(player.Hand))
-	g.PlayCard(player, cardIndex)
+	return &g.Player1
 }
 
 func InitializeDeck() []card.Card {
@@ -128,25 +266,30 @@ func InitializeDeck() []card.Card {
 	}
 
 	// Add some spell cards
-	deck = append(deck, card.CreateSpellCard("Fireball", "Deal 3 damage to the opponent", func(target interface{}) {
-		if player, ok := target.(*player.Player); ok {
-			player.TakeDamage(3)
+	deck = append(deck, card.CreateSpellCard("Fireball", 3, "Deal 3 damage to the opponent", func(target interface{}) {
+		if ctx, ok := target.(*PlayContext); ok {
+			ctx.DealDamage(3)
 		}
 	}))
 
-	deck = append(deck, card.CreateSpellCard("Heal", "Restore 3 health", func(target interface{}) {
-		if player, ok := target.(*player.Player); ok {
-			player.Heal(3)
+	deck = append(deck, card.CreateSpellCard("Heal", 2, "Restore 3 health", func(target interface{}) {
+		if ctx, ok := target.(*PlayContext); ok {
+			ctx.Game.HealPlayer(ctx.Source, ctx.Source, 3)
 		}
 	}))
 
 	// Add some item cards
-	deck = append(deck, card.CreateItemCard("Shield", 1, "Increase armor by 2", func(target interface{}) {
-		if player, ok := target.(*player.Player); ok {
-			player.AddArmor(2)
+	deck = append(deck, card.CreateItemCard("Shield", 1, 2, "Increase armor by 2", func(target interface{}) {
+		if ctx, ok := target.(*PlayContext); ok {
+			ctx.Source.AddArmor(2)
 		}
 	}))
 
+	// Card IDs follow the order above, so new cards must only be appended
+	for i := range deck {
+		deck[i].ID = i + 1
+	}
+
 	return deck
 }
 
@@ -157,25 +300,158 @@ func initializePlayers() (player.Player, player.Player) {
 	return *player1, *player2
 }
 
+// DeckOf returns the player's own draw pile.
+func (g *Game) DeckOf(p *player.Player) *[]card.Card {
+	if p == &g.Player2 {
+		return &g.Field.OpponentDeck
+	}
+	return &g.Field.PlayerDeck
+}
+
+// FieldOf returns the player's half of the field.
+func (g *Game) FieldOf(p *player.Player) *PlayerField {
+	if p == &g.Player2 {
+		return &g.Field.OpponentField
+	}
+	return &g.Field.PlayerField
+}
+
+// DiscardOf returns the player's own discard pile.
+func (g *Game) DiscardOf(p *player.Player) *[]card.Card {
+	if p == &g.Player2 {
+		return &g.Field.OpponentDiscard
+	}
+	return &g.Field.PlayerDiscard
+}
+
+// DeckList returns the deck the player chose at game start.
+func (g *Game) DeckList(p *player.Player) []card.Card {
+	return g.deckLists[g.seatIndex(p)]
+}
+
+// SetDeckList validates a deck and makes the player use it from the next Reset.
+func (g *Game) SetDeckList(p *player.Player, deck []card.Card) error {
+	if violations := ValidateDeck(deck, g.Rules.Deck); len(violations) > 0 {
+		return &DeckError{Owner: p.Name, Violations: violations}
+	}
+	g.deckLists[g.seatIndex(p)] = deck
+	return nil
+}
+
+func (g *Game) seatIndex(p *player.Player) int {
+	if p == &g.Player2 {
+		return 1
+	}
+	return 0
+}
+
+// Seed makes every shuffle of the game reproducible and reshuffles both
+// decks. Call it before the initial hands are dealt.
+func (g *Game) Seed(seed int64) {
+	g.source = &splitMix{state: uint64(seed)}
+	g.seeded = rand.New(g.source)
+	g.Rand = g.seeded
+	g.setupDecks()
+}
+
+func (g *Game) setupDecks() {
+	g.Field.PlayerDeck = append([]card.Card(nil), g.deckLists[0]...)
+	g.Field.OpponentDeck = append([]card.Card(nil), g.deckLists[1]...)
+	g.shuffleCards(g.Field.PlayerDeck)
+	g.shuffleCards(g.Field.OpponentDeck)
+	g.rehash(&g.Player1, zoneDeck)
+	g.rehash(&g.Player2, zoneDeck)
+}
+
+// DrawCard moves the top card of the player's deck into their hand. A card
+// drawn into a full hand is burned to the discard pile. Drawing from an
+// empty deck is handled according to Rules.DeckOut.
 func (g *Game) DrawCard(player *player.Player) {
-	if len(g.Deck) == 0 {
-		g.ShuffleDiscardPileToDeck()
+	deck := g.DeckOf(player)
+	if len(*deck) == 0 {
+		switch g.Rules.DeckOut {
+		case DeckOutReshuffle:
+			g.ShuffleDiscardPileToDeck(player)
+			if len(*deck) == 0 {
+				return
+			}
+		case DeckOutLoss:
+			g.deckedOut[g.seatIndex(player)] = true
+			g.emit(Event{
+				Kind:    EventDeckOut,
+				Player:  player.Name,
+				Message: fmt.Sprintf("%s has no cards left to draw", player.Name),
+			})
+			return
+		default:
+			g.fatigue(player)
+			return
+		}
 	}
-	if len(g.Deck) > 0 {
-		card := g.Deck[0]
-		g.Deck = g.Deck[1:]
-		player.Hand = append(player.Hand, card)
+
+	card := (*deck)[0]
+	g.drawTop(player)
+	*deck = (*deck)[1:]
+	if g.Rules.MaxHandSize > 0 && len(player.Hand) >= g.Rules.MaxHandSize {
+		discard := g.DiscardOf(player)
+		*discard = append(*discard, card)
+		g.put(player, zoneDiscard, card)
+		g.emit(Event{
+			Kind:    EventBurn,
+			Player:  player.Name,
+			Cards:   []string{card.Name},
+			Message: fmt.Sprintf("%s's hand is full, %s was burned", player.Name, card.Name),
+		})
+		return
 	}
+	player.Hand = append(player.Hand, card)
+	g.put(player, zoneHand, card)
+	g.emit(Event{Kind: EventDraw, Player: player.Name, Cards: []string{card.Name}})
 }
 
-func (g *Game) ShuffleDiscardPileToDeck() {
-	g.Deck = append(g.Deck, g.DiscardPile...)
-	g.DiscardPile = []card.Card{}
-	rand.Shuffle(len(g.Deck), func(i, j int) {
-		g.Deck[i], g.Deck[j] = g.Deck[j], g.Deck[i]
+// Concede ends the game with a win for the player's opponent.
+func (g *Game) Concede(p *player.Player) {
+	g.conceded[g.seatIndex(p)] = true
+	g.emit(Event{
+		Kind:    EventConcede,
+		Player:  p.Name,
+		Message: fmt.Sprintf("%s conceded", p.Name),
 	})
 }
 
+func (g *Game) fatigue(player *player.Player) {
+	seat := g.seatIndex(player)
+	g.fatigueDamage[seat]++
+	damage := g.fatigueDamage[seat]
+	player.TakeDamage(damage)
+	g.emit(Event{
+		Kind:    EventFatigue,
+		Player:  player.Name,
+		Amount:  damage,
+		Message: fmt.Sprintf("%s's deck is empty, fatigue deals %d damage", player.Name, damage),
+	})
+}
+
+// ShuffleDiscardPileToDeck moves the player's discard pile back into their deck.
+func (g *Game) ShuffleDiscardPileToDeck(player *player.Player) {
+	deck, discard := g.DeckOf(player), g.DiscardOf(player)
+	defer g.rehash(player, zoneDeck, zoneDiscard)
+	*deck = append(*deck, *discard...)
+	*discard = []card.Card{}
+	g.shuffleCards(*deck)
+}
+
+func (g *Game) shuffleCards(cards []card.Card) {
+	swap := func(i, j int) {
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+	if g.Rand != nil {
+		g.Rand.Shuffle(len(cards), swap)
+	} else {
+		rand.Shuffle(len(cards), swap)
+	}
+}
+
 func (g *Game) DealInitialHands() {
 	for i := 0; i < 5; i++ {
 		g.DrawCard(&g.Player1)
@@ -184,6 +460,7 @@ func (g *Game) DealInitialHands() {
 }
 
 func (g *Game) SwitchTurn() {
+	g.CurrentPlayer.TickModifiers()
 	if g.CurrentPlayer == &g.Player1 {
 		g.CurrentPlayer = &g.Player2
 	} else {
@@ -193,21 +470,33 @@ func (g *Game) SwitchTurn() {
 	g.CurrentPhase = DrawPhase
 }
 
+// StartTurn runs the draw phase for the current player and opens the play phase.
+func (g *Game) StartTurn() {
+	g.CurrentPhase = DrawPhase
+	g.DrawCard(g.CurrentPlayer)
+	g.CurrentPlayer.RestoreMana(g.CurrentPlayer.EffectiveMaxMana())
+	g.CurrentPhase = PlayPhase
+}
+
 func (g *Game) GameLoop() {
+	epoch := g.epoch.Load()
+	if g.TurnCount == 0 {
+		g.runMulligan(epoch)
+	}
+
 	for !g.GameOver {
-		g.CurrentPhase = DrawPhase
-		g.DrawCard(g.CurrentPlayer)
-		g.CurrentPhase = PlayPhase
-
-		if g.CurrentPlayer == &g.Player1 {
-			// Player 1's turn (human player)
-			fmt.Printf("%s's turn\n", g.CurrentPlayer.Name)
-			// Wait for the player to end their turn
-			<-g.EndTurn
-		} else {
-			// Player 2's turn (opponent)
-			g.PlayRandomCard(g.CurrentPlayer)
-			time.Sleep(1 * time.Second) // Simulate opponent thinking
+		if g.stale(epoch) {
+			return
+		}
+		g.StartTurn()
+		if g.UIUpdate != nil {
+			g.UIUpdate()
+		}
+
+		g.logf("%s's turn\n", g.CurrentPlayer.Name)
+		g.playTurn(g.CurrentPlayer, epoch)
+		if g.stale(epoch) {
+			return
 		}
 
 		g.CurrentPhase = EndPhase
@@ -228,26 +517,89 @@ func (g *Game) GameLoop() {
 	g.DetermineWinner()
 }
 
+// stale reports whether the game was reset since the loop started at epoch.
+func (g *Game) stale(epoch int64) bool {
+	return g.epoch.Load() != epoch
+}
+
 func (g *Game) CheckGameOver() bool {
-	return len(g.Player1.Hand) == 0 || len(g.Player2.Hand) == 0 || g.Player1.Health <= 0 || g.Player2.Health <= 0 || g.TurnCount >= 20
+	return g.Result().Reason != EndNone
+}
+
+// Result works out whether the game has ended, why, and who won.
+func (g *Game) Result() Result {
+	p1, p2 := &g.Player1, &g.Player2
+	if g.Rules.SeatOrder {
+		if reason := g.lost(p1); reason != EndNone {
+			return Result{Winner: p2, Reason: reason}
+		}
+		if reason := g.lost(p2); reason != EndNone {
+			return Result{Winner: p1, Reason: reason}
+		}
+	}
+	switch {
+	case p1.Health <= 0 && p2.Health <= 0:
+		return Result{Reason: EndHealth}
+	case p1.Health <= 0:
+		return Result{Winner: p2, Reason: EndHealth}
+	case p2.Health <= 0:
+		return Result{Winner: p1, Reason: EndHealth}
+	case g.conceded[0]:
+		return Result{Winner: p2, Reason: EndConcede}
+	case g.conceded[1]:
+		return Result{Winner: p1, Reason: EndConcede}
+	case g.deckedOut[0]:
+		return Result{Winner: p2, Reason: EndDeckOut}
+	case g.deckedOut[1]:
+		return Result{Winner: p1, Reason: EndDeckOut}
+	}
+
+	if g.Rules.EmptyHand == EmptyHandLoses {
+		if len(p1.Hand) == 0 {
+			return Result{Winner: p2, Reason: EndEmptyHand}
+		}
+		if len(p2.Hand) == 0 {
+			return Result{Winner: p1, Reason: EndEmptyHand}
+		}
+	}
+
+	if g.Rules.TurnLimit > 0 && g.TurnCount >= g.Rules.TurnLimit {
+		result := Result{Reason: EndTurnLimit}
+		if p1.Score > p2.Score {
+			result.Winner = p1
+		} else if p2.Score > p1.Score {
+			result.Winner = p2
+		} else if g.Rules.HealthTiebreak && p1.Health != p2.Health {
+			result.Winner = p1
+			if p2.Health > p1.Health {
+				result.Winner = p2
+			}
+		}
+		return result
+	}
+
+	return Result{Reason: EndNone}
+}
+
+// lost returns why p has lost on health or an empty hand, or EndNone.
+func (g *Game) lost(p *player.Player) EndReason {
+	if p.Health <= 0 {
+		return EndHealth
+	}
+	if g.Rules.EmptyHand == EmptyHandLoses && len(p.Hand) == 0 {
+		return EndEmptyHand
+	}
+	return EndNone
 }
 
 func (g *Game) DetermineWinner() {
-	var winner string
-	if g.Player1.Health <= 0 || len(g.Player1.Hand) == 0 {
-		winner = g.Player2.Name
-	} else if g.Player2.Health <= 0 || len(g.Player2.Hand) == 0 {
-		winner = g.Player1.Name
-	} else if g.Player1.Score > g.Player2.Score {
-		winner = g.Player1.Name
-	} else if g.Player2.Score > g.Player1.Score {
-		winner = g.Player2.Name
-	} else {
-		winner = "It's a tie!"
+	winner := "It's a tie!"
+	if w := g.Result().Winner; w != nil {
+		winner = w.Name
 	}
 
-	fmt.Printf("Game Over! %s wins!\n", winner)
-	fmt.Printf("Final Score: %s: %d, %s: %d\n", g.Player1.Name, g.Player1.Score, g.Player2.Name, g.Player2.Score)
+	g.logf("Game Over! %s wins!\n", winner)
+	g.logf("Final Score: %s: %d, %s: %d\n", g.Player1.Name, g.Player1.Score, g.Player2.Name, g.Player2.Score)
 
 	// Update UI with game over state
 	if g.UIUpdate != nil {
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"io"
+	"testing"
+
+	"GoGame/internal/card"
+	"GoGame/internal/player"
+)
+
+func TestPlayCardSpendsMana(t *testing.T) {
+	g, err := NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.Player1.EquipItem(&player.Item{Name: "Ring", Bonus: 2}, "ring")
+	g.StartTurn()
+	if g.Player1.Mana != 12 {
+		t.Fatalf("mana %d at the start of the turn, want 10 + 2 from the ring", g.Player1.Mana)
+	}
+
+	g.Player1.Hand = []card.Card{card.CreateBasicUnitCard("Titan", 9), card.CreateBasicUnitCard("Hero", 6)}
+	if err := g.Apply(&g.Player1, Action{Kind: ActionPlayCard, CardIndex: 0}); err != nil {
+		t.Fatal(err)
+	}
+	if g.Player1.Mana != 3 || len(g.Player1.Hand) != 1 {
+		t.Fatalf("mana %d, hand %d after playing the titan", g.Player1.Mana, len(g.Player1.Hand))
+	}
+
+	if legal := g.ViewFor(&g.Player1).LegalActions(); len(legal) != 1 || legal[0].Kind != ActionEndTurn {
+		t.Fatalf("legal actions %v, want only ending the turn", legal)
+	}
+	g.PlayCard(&g.Player1, 0)
+	if g.Player1.Mana != 3 || len(g.Player1.Hand) != 1 {
+		t.Fatalf("played a card costing more than the mana left: mana %d, hand %d", g.Player1.Mana, len(g.Player1.Hand))
+	}
+}
+
+func TestEachSeatDrawsFromItsOwnDeck(t *testing.T) {
+	soldiers := []card.Card{card.CreateBasicUnitCard("Soldier", 1), card.CreateBasicUnitCard("Soldier", 1)}
+	archers := []card.Card{card.CreateBasicUnitCard("Archer", 2), card.CreateBasicUnitCard("Archer", 2)}
+	g, err := NewGameWithDecks(soldiers, archers, LegacyRules())
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+
+	g.DrawCard(&g.Player1)
+	g.DrawCard(&g.Player2)
+	g.DrawCard(&g.Player2)
+	if len(g.Player1.Hand) != 1 || g.Player1.Hand[0].Name != "Soldier" {
+		t.Fatalf("player 1 drew %v, want one soldier", g.Player1.Hand)
+	}
+	if len(g.Player2.Hand) != 2 || g.Player2.Hand[0].Name != "Archer" || g.Player2.Hand[1].Name != "Archer" {
+		t.Fatalf("player 2 drew %v, want two archers", g.Player2.Hand)
+	}
+	if len(*g.DeckOf(&g.Player1)) != 1 || len(*g.DeckOf(&g.Player2)) != 0 {
+		t.Fatalf("decks left with %d and %d cards, want 1 and 0", len(*g.DeckOf(&g.Player1)), len(*g.DeckOf(&g.Player2)))
+	}
+}
+
+func TestEmptyDeckReshufflesTheDiscardPile(t *testing.T) {
+	deck := []card.Card{card.CreateBasicUnitCard("Soldier", 1)}
+	g, err := NewGameWithDecks(deck, deck, LegacyRules())
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+
+	g.DrawCard(&g.Player1)
+	*g.DiscardOf(&g.Player1) = []card.Card{card.CreateBasicUnitCard("Knight", 3), card.CreateBasicUnitCard("Knight", 3)}
+	g.DrawCard(&g.Player1)
+	if len(g.Player1.Hand) != 2 || g.Player1.Hand[1].Name != "Knight" {
+		t.Fatalf("hand %v, want a knight drawn from the reshuffled discard pile", g.Player1.Hand)
+	}
+	if len(*g.DiscardOf(&g.Player1)) != 0 || len(*g.DeckOf(&g.Player1)) != 1 {
+		t.Fatalf("discard %d and deck %d after the reshuffle, want 0 and 1", len(*g.DiscardOf(&g.Player1)), len(*g.DeckOf(&g.Player1)))
+	}
+	if len(*g.DeckOf(&g.Player2)) != 1 {
+		t.Fatal("the reshuffle touched the other seat's deck")
+	}
+}
+
+func TestDrawIntoAFullHandBurnsTheCard(t *testing.T) {
+	g, err := NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.Rules.MaxHandSize = 2
+	g.DrawCard(&g.Player1)
+	g.DrawCard(&g.Player1)
+	top := (*g.DeckOf(&g.Player1))[0]
+
+	g.DrawCard(&g.Player1)
+	discard := *g.DiscardOf(&g.Player1)
+	if len(g.Player1.Hand) != 2 || len(discard) != 1 || discard[0].Name != top.Name {
+		t.Fatalf("hand %d, discard %v after drawing into a full hand, want %s burned", len(g.Player1.Hand), cardNames(discard), top.Name)
+	}
+	if last := g.Events[len(g.Events)-1]; last.Kind != EventBurn || last.Cards[0] != top.Name {
+		t.Fatalf("last event %+v, want the burn of %s", last, top.Name)
+	}
+}
+
+func TestFatigueGrowsAndGoesThroughArmor(t *testing.T) {
+	g, err := NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	*g.DeckOf(&g.Player1) = nil
+	g.Player1.AddArmor(2)
+
+	for _, want := range []struct{ health, armor int }{{100, 1}, {99, 0}, {96, 0}} {
+		g.DrawCard(&g.Player1)
+		if g.Player1.Health != want.health || g.Player1.Armor != want.armor {
+			t.Fatalf("health %d armor %d, want %d and %d", g.Player1.Health, g.Player1.Armor, want.health, want.armor)
+		}
+	}
+	if v := g.ViewFor(&g.Player2); v.Opponent.Fatigue != 3 {
+		t.Fatalf("fatigue %d after three empty draws, want 3", v.Opponent.Fatigue)
+	}
+}
+
+func TestDeckOutLoses(t *testing.T) {
+	g, err := NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.Rules.DeckOut = DeckOutLoss
+	*g.DeckOf(&g.Player2) = nil
+
+	g.DrawCard(&g.Player2)
+	if r := g.Result(); r.Winner != &g.Player1 || r.Reason != EndDeckOut {
+		t.Fatalf("result %+v after player 2 decked out, want a win for player 1", r)
+	}
+	if g.Player2.Health != 100 {
+		t.Fatalf("a deck-out loss dealt fatigue: health %d", g.Player2.Health)
+	}
+}
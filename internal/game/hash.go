@@ -0,0 +1,205 @@
+package game
+
+import (
+	"hash/fnv"
+
+	"GoGame/internal/card"
+	"GoGame/internal/player"
+)
+
+// zone is a card area of one seat.
+type zone int
+
+const (
+	zoneHand zone = iota
+	zoneDeck
+	zoneDiscard
+	zoneField
+	numZones
+)
+
+// zoneHashes holds the hash of every zone of both seats. The engine updates
+// it as cards move, and Rehash computes it from scratch. The zero value is
+// the hash of empty zones.
+type zoneHashes [2][numZones]uint64
+
+// Hash keys, kept apart so equal values in different places hash differently.
+const (
+	keyTurn uint64 = iota + 1
+	keyPhase
+	keyCurrent
+	keyGameOver
+	keySeat
+	keyStat
+	keyItem
+	keyModifier
+	keyCard
+)
+
+// Hash returns a 64-bit Zobrist hash of the game state: hands, deck
+// order, discard piles, field slots, player stats, items and modifiers,
+// turn and phase. Every feature contributes an independent key and the keys
+// are combined with XOR.
+//
+// The card zones are hashed incrementally. A card in a hand or discard pile
+// has one key wherever it sits, added in when the card arrives and taken
+// out when it leaves; the keys are summed rather than XORed so two copies
+// of a card do not cancel out. Deck keys include the position counted from
+// the bottom, so drawing the top card leaves the other keys alone and the
+// deck order is part of the hash; a shuffle hashes the deck again. Hash
+// combines the zone hashes with the small per-seat state and does not write
+// to the game, so it may be called concurrently with other readers.
+//
+// The hash depends only on the state, never on pointers or the process, so
+// two peers or a replay can compare hashes to detect a desync. Code that
+// edits hands, decks or the field directly must call Rehash afterwards, or
+// the zone hashes go stale.
+func (g *Game) Hash() uint64 {
+	h := mix(keyTurn, uint64(g.TurnCount)) ^
+		mix(keyPhase, uint64(g.CurrentPhase)) ^
+		mix(keyCurrent, uint64(g.currentSeat()+1))
+	if g.GameOver {
+		h ^= mix(keyGameOver)
+	}
+	for seat, p := range []*player.Player{&g.Player1, &g.Player2} {
+		h ^= g.seatHash(seat, p)
+		for z := zone(0); z < numZones; z++ {
+			h ^= g.zones[seat][z]
+		}
+	}
+	return h
+}
+
+// Rehash computes the hash of every card zone from scratch.
+func (g *Game) Rehash() {
+	g.rehash(&g.Player1, zoneHand, zoneDeck, zoneDiscard, zoneField)
+	g.rehash(&g.Player2, zoneHand, zoneDeck, zoneDiscard, zoneField)
+}
+
+// rehash computes the hashes of zones of the player's seat from scratch.
+func (g *Game) rehash(p *player.Player, zones ...zone) {
+	seat := g.seatIndex(p)
+	for _, z := range zones {
+		g.zones[seat][z] = g.zoneHash(seat, p, z)
+	}
+}
+
+// put adds a card that arrives in a hand or discard pile to its hash.
+func (g *Game) put(p *player.Player, z zone, c card.Card) {
+	seat := g.seatIndex(p)
+	g.zones[seat][z] += cardHash(seat, z, 0, c)
+}
+
+// take removes a card that leaves a hand or discard pile from its hash.
+func (g *Game) take(p *player.Player, z zone, c card.Card) {
+	seat := g.seatIndex(p)
+	g.zones[seat][z] -= cardHash(seat, z, 0, c)
+}
+
+// drawTop removes the top card of the player's deck from the deck hash.
+// Call it before the card is taken off the deck.
+func (g *Game) drawTop(p *player.Player) {
+	seat, deck := g.seatIndex(p), *g.DeckOf(p)
+	g.zones[seat][zoneDeck] ^= cardHash(seat, zoneDeck, len(deck)-1, deck[0])
+}
+
+// seatHash covers the small per-seat state, which is cheaper to hash again
+// than to track.
+func (g *Game) seatHash(seat int, p *player.Player) uint64 {
+	s := uint64(seat)
+	h := mix(keySeat, s, 0, hashString(p.Name)) ^
+		mix(keySeat, s, 1, boolBit(g.mulliganDone[seat])) ^
+		mix(keySeat, s, 2, boolBit(g.deckedOut[seat])) ^
+		mix(keySeat, s, 3, uint64(g.fatigueDamage[seat])) ^
+		mix(keySeat, s, 4, boolBit(g.conceded[seat]))
+
+	stats := []int{p.Score, p.Health, p.MaxHealth, p.Mana, p.MaxMana, p.Armor}
+	for i, v := range stats {
+		h ^= mix(keyStat, s, uint64(i), uint64(v))
+	}
+	for i, item := range []*player.Item{p.Ring, p.Necklace, p.Weapon} {
+		if item != nil {
+			h ^= mix(keyItem, s, uint64(i), hashString(item.Name), hashString(item.Description), uint64(item.Bonus))
+		}
+	}
+	for i, m := range p.Modifiers {
+		h ^= mix(keyModifier, s, uint64(i), hashString(m.Source), uint64(m.Stat), uint64(m.Kind),
+			uint64(m.Value), uint64(m.Duration), uint64(m.Priority))
+	}
+	return h
+}
+
+func (g *Game) zoneHash(seat int, p *player.Player, z zone) uint64 {
+	var cards []card.Card
+	switch {
+	case z == zoneHand:
+		cards = p.Hand
+	case g.Field == nil:
+		return 0
+	case z == zoneDeck:
+		cards = *g.DeckOf(p)
+	case z == zoneDiscard:
+		cards = *g.DiscardOf(p)
+	case z == zoneField:
+		return fieldHash(seat, g.FieldOf(p))
+	}
+
+	var h uint64
+	for i, c := range cards {
+		switch z {
+		case zoneDeck:
+			h ^= cardHash(seat, z, len(cards)-1-i, c)
+		default:
+			h += cardHash(seat, z, 0, c)
+		}
+	}
+	return h
+}
+
+func fieldHash(seat int, pf *PlayerField) uint64 {
+	slots := append(append(pf.LeftCards[:], pf.RightCards[:]...), pf.PlayerCard)
+	var h uint64
+	for i, slot := range slots {
+		if slot.IsOccupied && slot.Card != nil {
+			h ^= cardHash(seat, zoneField, i, *slot.Card)
+		}
+	}
+	return h
+}
+
+// cardHash is the key of card c at position i of a zone; zones that do not
+// keep an order use position 0. Cards are identified by their data;
+// effects are shared by cards with the same ID.
+func cardHash(seat int, z zone, i int, c card.Card) uint64 {
+	return mix(keyCard, uint64(seat), uint64(z), uint64(i), uint64(c.ID), hashString(c.Name),
+		uint64(c.Power), uint64(c.Cost), uint64(c.Type))
+}
+
+// mix folds values into a single well-distributed key.
+func mix(values ...uint64) uint64 {
+	var h uint64
+	for _, v := range values {
+		h = finalize(h ^ v + 0x9e3779b97f4a7c15)
+	}
+	return h
+}
+
+// finalize is the splitmix64 output function.
+func finalize(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func boolBit(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,191 @@
+package game
+
+import (
+	"io"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"GoGame/internal/card"
+	"GoGame/internal/player"
+)
+
+// freshHash hashes the game with every zone hashed from scratch.
+func freshHash(g *Game) uint64 {
+	c := g.Clone()
+	c.Rehash()
+	return c.Hash()
+}
+
+func TestHashTracksPlayedGame(t *testing.T) {
+	g := newTestGame(t)
+	rng := rand.New(rand.NewSource(7))
+	check := func(step string) {
+		t.Helper()
+		if got, want := g.Hash(), freshHash(g); got != want {
+			t.Fatalf("after %s: cached hash %x, recomputed %x", step, got, want)
+		}
+	}
+
+	check("setup")
+	for turn := 0; turn < 30 && !g.CheckGameOver(); turn++ {
+		g.StartTurn()
+		check("start of turn")
+		p := g.CurrentPlayer
+		for {
+			legal := g.ViewFor(p).LegalActions()
+			a := legal[rng.Intn(len(legal))]
+			if a.Kind == ActionEndTurn {
+				break
+			}
+			if err := g.Apply(p, a); err != nil {
+				t.Fatal(err)
+			}
+			check(a.String())
+		}
+		g.SwitchTurn()
+		check("switch turn")
+	}
+}
+
+func TestHashIsReadOnly(t *testing.T) {
+	g := newTestGame(t)
+	g.Player1.Hand = g.Player1.Hand[1:]
+	zones := g.zones
+
+	want := freshHash(g)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.Hash()
+		}()
+	}
+	wg.Wait()
+	if g.zones != zones {
+		t.Fatal("Hash changed the zone hashes")
+	}
+	if g.Hash() == want {
+		t.Fatal("direct edit seen without Rehash; the zone hashes are not used")
+	}
+	g.Rehash()
+	if g.Hash() != want {
+		t.Fatal("Rehash did not pick up the direct edit")
+	}
+}
+
+func TestHashEqualGames(t *testing.T) {
+	a, b := newTestGame(t), newTestGame(t)
+	if a.Hash() != b.Hash() {
+		t.Fatal("games built the same way hash differently")
+	}
+	if c := a.Clone(); c.Hash() != a.Hash() {
+		t.Fatal("clone hashes differently")
+	}
+}
+
+func TestHashSeesChanges(t *testing.T) {
+	changes := map[string]func(g *Game){
+		"deck order": func(g *Game) {
+			deck := g.Field.PlayerDeck
+			for i := range deck {
+				if !deck[i].Same(deck[0]) {
+					deck[0], deck[i] = deck[i], deck[0]
+					return
+				}
+			}
+		},
+		"hand":    func(g *Game) { g.Player2.Hand = g.Player2.Hand[1:] },
+		"slot":    func(g *Game) { g.Field.OpponentField.RemoveCard(0, true) },
+		"discard": func(g *Game) { g.Field.PlayerDiscard = append(g.Field.PlayerDiscard, g.Player1.Hand[0]) },
+		"health":  func(g *Game) { g.Player1.Health-- },
+		"item":    func(g *Game) { g.Player1.Ring.Bonus++ },
+		"phase":   func(g *Game) { g.CurrentPhase = EndPhase },
+		"turn":    func(g *Game) { g.TurnCount++ },
+		"current": func(g *Game) { g.CurrentPlayer = g.Opponent(g.CurrentPlayer) },
+	}
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			g := newTestGame(t)
+			before := g.Hash()
+			change(g)
+			g.Rehash()
+			if g.Hash() == before {
+				t.Fatal("hash did not change")
+			}
+		})
+	}
+}
+
+func TestIncrementalHashMatchesRehash(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		rules := DefaultRules()
+		rules.MaxHandSize = 4
+		if seed%2 == 0 {
+			rules.DeckOut = DeckOutReshuffle
+		}
+		g, err := NewGameWithDecks(InitializeDeck(), InitializeDeck(), rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.Output = io.Discard
+		g.Seed(seed)
+		rng := rand.New(rand.NewSource(seed))
+		check := func(step string) {
+			t.Helper()
+			if got, want := g.Hash(), freshHash(g); got != want {
+				t.Fatalf("seed %d after %s: incremental hash %x, from scratch %x", seed, step, got, want)
+			}
+		}
+
+		g.DealInitialHands()
+		check("deal")
+		for _, p := range []*player.Player{&g.Player1, &g.Player2} {
+			var redraw []int
+			for i := range p.Hand {
+				if rng.Intn(2) == 0 {
+					redraw = append(redraw, i)
+				}
+			}
+			if err := g.Mulligan(p, redraw); err != nil {
+				t.Fatal(err)
+			}
+			check("mulligan")
+		}
+		for turn := 0; turn < 40 && !g.CheckGameOver(); turn++ {
+			g.StartTurn()
+			check("start of turn")
+			p := g.CurrentPlayer
+			for {
+				legal := g.ViewFor(p).LegalActions()
+				a := legal[rng.Intn(len(legal))]
+				if a.Kind == ActionEndTurn {
+					break
+				}
+				if err := g.Apply(p, a); err != nil {
+					t.Fatal(err)
+				}
+				check(a.String())
+			}
+			g.SwitchTurn()
+			check("switch turn")
+		}
+	}
+}
+
+func TestHashKeepsCopiesApart(t *testing.T) {
+	g := newTestGame(t)
+	soldier := card.CreateBasicUnitCard("Soldier", 1)
+	g.Player1.Hand = []card.Card{soldier}
+	g.Rehash()
+	one := g.Hash()
+	g.Player1.Hand = []card.Card{soldier, soldier}
+	g.Rehash()
+	two := g.Hash()
+	g.Player1.Hand = nil
+	g.Rehash()
+	if none := g.Hash(); one == two || two == none {
+		t.Fatal("two copies of a card hash like one or none")
+	}
+}
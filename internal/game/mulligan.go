@@ -0,0 +1,164 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"GoGame/internal/card"
+	"GoGame/internal/player"
+)
+
+// MulliganMode selects how much of the opening hand may be redrawn.
+type MulliganMode int
+
+const (
+	// MulliganNone skips the mulligan step.
+	MulliganNone MulliganMode = iota
+	// MulliganFull lets a player redraw the whole hand or keep it.
+	MulliganFull
+	// MulliganPartial lets a player pick any cards to redraw.
+	MulliganPartial
+)
+
+// Mulligan shuffles the chosen cards of the opening hand back into the
+// player's deck and draws as many replacements. Each player may mulligan
+// only once, before the first draw phase.
+func (g *Game) Mulligan(p *player.Player, indices []int) error {
+	seat := g.seatIndex(p)
+	if g.Rules.Mulligan == MulliganNone {
+		return fmt.Errorf("mulligan is disabled")
+	}
+	if g.mulliganDone[seat] {
+		return fmt.Errorf("%s has already taken a mulligan", p.Name)
+	}
+
+	indices = normalizeIndices(indices)
+	if err := checkMulligan(g.Rules.Mulligan, len(p.Hand), indices); err != nil {
+		return err
+	}
+
+	var returned []card.Card
+	for j := len(indices) - 1; j >= 0; j-- {
+		i := indices[j]
+		returned = append(returned, p.Hand[i])
+		g.take(p, zoneHand, p.Hand[i])
+		p.Hand = append(p.Hand[:i], p.Hand[i+1:]...)
+	}
+	deck := g.DeckOf(p)
+	*deck = append(*deck, returned...)
+	g.shuffleCards(*deck)
+	g.rehash(p, zoneDeck)
+	for range returned {
+		g.DrawCard(p)
+	}
+
+	g.mulliganDone[seat] = true
+	g.emit(Event{
+		Kind:    EventMulligan,
+		Player:  p.Name,
+		Cards:   cardNames(returned),
+		Indices: indices,
+		Amount:  len(returned),
+		Message: fmt.Sprintf("%s redrew %d cards", p.Name, len(returned)),
+	})
+	return nil
+}
+
+// CheckMulligan reports whether Mulligan would accept indices for the
+// viewer's hand, so a controller can reject a bad choice before the game
+// sees it.
+func (v View) CheckMulligan(indices []int) error {
+	return checkMulligan(v.Rules.Mulligan, len(v.Self.Hand), normalizeIndices(indices))
+}
+
+func checkMulligan(mode MulliganMode, handSize int, indices []int) error {
+	for _, i := range indices {
+		if i < 0 || i >= handSize {
+			return fmt.Errorf("card index %d out of range", i)
+		}
+	}
+	if mode == MulliganFull && len(indices) != 0 && len(indices) != handSize {
+		return fmt.Errorf("full mulligan must redraw the whole hand")
+	}
+	return nil
+}
+
+// MulliganDone reports whether the player has finished the mulligan step.
+func (g *Game) MulliganDone(p *player.Player) bool {
+	return g.Rules.Mulligan == MulliganNone || g.mulliganDone[g.seatIndex(p)]
+}
+
+// AIMulligan is the computer player's mulligan policy: it sends back cards
+// it cannot afford on an early turn and weak units, keeping everything else.
+// Under MulliganFull it redraws only when most of the hand is unwanted.
+func AIMulligan(v View) []int {
+	hand := v.Self.Hand
+	var unwanted []int
+	for i, c := range hand {
+		tooExpensive := c.Cost > v.Self.MaxMana/2
+		weakUnit := c.Type == card.UnitCard && c.Power <= 2
+		if tooExpensive || weakUnit {
+			unwanted = append(unwanted, i)
+		}
+	}
+
+	switch v.Rules.Mulligan {
+	case MulliganFull:
+		if len(unwanted)*2 <= len(hand) {
+			return nil
+		}
+		all := make([]int, len(hand))
+		for i := range all {
+			all[i] = i
+		}
+		return all
+	case MulliganPartial:
+		return unwanted
+	default:
+		return nil
+	}
+}
+
+// runMulligan asks both controllers for their mulligan before the first turn.
+func (g *Game) runMulligan(epoch int64) {
+	if g.Rules.Mulligan == MulliganNone {
+		return
+	}
+	g.CurrentPhase = MulliganPhase
+	if g.UIUpdate != nil {
+		g.UIUpdate()
+	}
+
+	for _, p := range []*player.Player{&g.Player1, &g.Player2} {
+		if g.MulliganDone(p) {
+			continue
+		}
+		indices := g.Controller(p).Mulligan(g.ViewFor(p))
+		if g.stale(epoch) {
+			return
+		}
+		if err := g.Mulligan(p, indices); err != nil {
+			g.Mulligan(p, nil)
+		}
+	}
+}
+
+func normalizeIndices(indices []int) []int {
+	sorted := append([]int(nil), indices...)
+	sort.Ints(sorted)
+	unique := sorted[:0]
+	for i, v := range sorted {
+		if i == 0 || v != sorted[i-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+func cardNames(cards []card.Card) []string {
+	names := make([]string, len(cards))
+	for i, c := range cards {
+		names[i] = c.Name
+	}
+	return names
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"GoGame/internal/card"
+)
+
+func newMulliganGame(t *testing.T, mode MulliganMode) *Game {
+	t.Helper()
+	rules := DefaultRules()
+	rules.Mulligan = mode
+	g, err := NewGameWithDecks(InitializeDeck(), InitializeDeck(), rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Output = io.Discard
+	g.Seed(1)
+	g.DealInitialHands()
+	return g
+}
+
+func TestMulliganNoneIsRejected(t *testing.T) {
+	g := newMulliganGame(t, MulliganNone)
+	if err := g.Mulligan(&g.Player1, []int{0}); err == nil {
+		t.Fatal("mulligan accepted with mulligans disabled")
+	}
+	if !g.MulliganDone(&g.Player1) {
+		t.Fatal("mulligan step not done with mulligans disabled")
+	}
+}
+
+func TestMulliganFullRedrawsAllOrNothing(t *testing.T) {
+	g := newMulliganGame(t, MulliganFull)
+	if err := g.Mulligan(&g.Player1, []int{0, 1}); err == nil {
+		t.Fatal("full mulligan accepted part of the hand")
+	}
+	if g.MulliganDone(&g.Player1) {
+		t.Fatal("a rejected mulligan used up the player's mulligan")
+	}
+	if err := g.Mulligan(&g.Player1, []int{4, 3, 2, 1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Player1.Hand) != 5 || len(*g.DeckOf(&g.Player1)) != len(InitializeDeck())-5 {
+		t.Fatalf("hand %d, deck %d after a full mulligan", len(g.Player1.Hand), len(*g.DeckOf(&g.Player1)))
+	}
+	if err := g.Mulligan(&g.Player1, nil); err == nil {
+		t.Fatal("a second mulligan was accepted")
+	}
+}
+
+func TestMulliganPartialKeepsTheRest(t *testing.T) {
+	g := newMulliganGame(t, MulliganPartial)
+	kept := append([]card.Card(nil), g.Player1.Hand[1:]...)
+	if err := g.Mulligan(&g.Player1, []int{0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Player1.Hand) != 5 || !reflect.DeepEqual(g.Player1.Hand[:4], kept) {
+		t.Fatalf("hand %v, want %v and one new card", cardNames(g.Player1.Hand), cardNames(kept))
+	}
+	if err := g.Mulligan(&g.Player2, []int{5}); err == nil {
+		t.Fatal("accepted an index past the end of the hand")
+	}
+}
+
+func TestAIMulligan(t *testing.T) {
+	hand := []card.Card{
+		card.CreateBasicUnitCard("Soldier", 1),
+		card.CreateBasicUnitCard("Knight", 3),
+		card.CreateBasicUnitCard("Titan", 9),
+		card.CreateBasicUnitCard("Mage", 4),
+	}
+	view := func(mode MulliganMode, hand []card.Card) View {
+		v := View{Rules: DefaultRules()}
+		v.Rules.Mulligan = mode
+		v.Self.Hand, v.Self.MaxMana = hand, 10
+		return v
+	}
+
+	if got := AIMulligan(view(MulliganPartial, hand)); !reflect.DeepEqual(got, []int{0, 2}) {
+		t.Fatalf("partial mulligan %v, want the weak soldier and the costly titan", got)
+	}
+	if got := AIMulligan(view(MulliganFull, hand)); got != nil {
+		t.Fatalf("full mulligan %v with half the hand wanted, want to keep it", got)
+	}
+	bad := append(hand[:3:3], card.CreateBasicUnitCard("Archer", 2))
+	if got := AIMulligan(view(MulliganFull, bad)); !reflect.DeepEqual(got, []int{0, 1, 2, 3}) {
+		t.Fatalf("full mulligan %v with most of the hand unwanted, want all of it", got)
+	}
+	if got := AIMulligan(view(MulliganNone, bad)); got != nil {
+		t.Fatalf("mulligan %v with mulligans disabled", got)
+	}
+}
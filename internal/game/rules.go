@@ -0,0 +1,72 @@
+package game
+
+// Rules collects the configurable rules of a game.
+type Rules struct {
+	Deck     DeckRules    `json:"deck"`
+	Mulligan MulliganMode `json:"mulligan"`
+
+	// MaxHandSize is the most cards a hand may hold; cards drawn past it
+	// are burned. 0 means no limit.
+	MaxHandSize int `json:"max_hand_size"`
+
+	EmptyHand EmptyHandRule `json:"empty_hand"`
+	DeckOut   DeckOutRule   `json:"deck_out"`
+
+	// TurnLimit ends the game after this many turns; 0 means no limit.
+	TurnLimit int `json:"turn_limit"`
+	// SeatOrder checks player 1's losing conditions before player 2's, so
+	// a game both players lose at once goes to player 2, as in the original
+	// game. Otherwise such a game is a draw.
+	SeatOrder bool `json:"seat_order,omitempty"`
+	// HealthTiebreak gives a game that reaches the turn limit with equal
+	// scores to the player with more health instead of calling a tie.
+	HealthTiebreak bool `json:"health_tiebreak"`
+}
+
+// EmptyHandRule decides what happens when a player's hand is empty.
+type EmptyHandRule int
+
+const (
+	// EmptyHandAllowed lets play go on with an empty hand.
+	EmptyHandAllowed EmptyHandRule = iota
+	// EmptyHandLoses ends the game and the player with no cards loses.
+	EmptyHandLoses
+)
+
+// DeckOutRule decides what happens when a player draws from an empty deck.
+type DeckOutRule int
+
+const (
+	// DeckOutFatigue deals growing fatigue damage on every empty draw.
+	DeckOutFatigue DeckOutRule = iota
+	// DeckOutLoss makes the player lose on their first empty draw.
+	DeckOutLoss
+	// DeckOutReshuffle shuffles the discard pile back into the deck.
+	DeckOutReshuffle
+)
+
+// DefaultRules returns the rules used by NewGame.
+func DefaultRules() Rules {
+	return Rules{
+		Deck:     DefaultDeckRules(),
+		Mulligan: MulliganPartial,
+
+		MaxHandSize: 10,
+		EmptyHand:   EmptyHandAllowed,
+		DeckOut:     DeckOutFatigue,
+		TurnLimit:   20,
+	}
+}
+
+// LegacyRules returns the rules of the original game: any deck, no
+// mulligan, no hand limit, the discard pile is reshuffled into an empty
+// deck, a player with an empty hand loses and player 1 is checked first.
+func LegacyRules() Rules {
+	return Rules{
+		Mulligan:  MulliganNone,
+		EmptyHand: EmptyHandLoses,
+		DeckOut:   DeckOutReshuffle,
+		TurnLimit: 20,
+		SeatOrder: true,
+	}
+}
@@ -0,0 +1,45 @@
+package game
+
+import (
+	"testing"
+
+	"GoGame/internal/card"
+)
+
+func TestLegacyRulesCheckPlayer1First(t *testing.T) {
+	g, err := NewGameWithDecks(InitializeDeck(), InitializeDeck(), LegacyRules())
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.DealInitialHands()
+
+	g.Player1.Health, g.Player2.Health = 0, 0
+	if r := g.Result(); r.Winner != &g.Player2 || r.Reason != EndHealth {
+		t.Fatalf("both dead: %+v, want player 2 to win as in the original game", r)
+	}
+
+	g.Player1.Health = 10
+	g.Player1.Hand = nil
+	if r := g.Result(); r.Winner != &g.Player2 || r.Reason != EndEmptyHand {
+		t.Fatalf("player 1 out of cards, player 2 dead: %+v, want player 2 to win", r)
+	}
+
+	g.Rules.SeatOrder = false
+	if r := g.Result(); r.Winner != &g.Player1 || r.Reason != EndHealth {
+		t.Fatalf("without seat order: %+v, want player 1 to win on health", r)
+	}
+	g.Player1.Health = 0
+	if r := g.Result(); r.Winner != nil {
+		t.Fatalf("without seat order both dead: %+v, want a draw", r)
+	}
+}
+
+func TestLegacyRulesAcceptAnyDeck(t *testing.T) {
+	small := []card.Card{card.CreateBasicUnitCard("Soldier", 1)}
+	if _, err := NewGameWithDecks(small, small, LegacyRules()); err != nil {
+		t.Fatalf("legacy rules rejected a one-card deck: %v", err)
+	}
+	if _, err := NewGameWithDecks(small, small, DefaultRules()); err == nil {
+		t.Fatal("default rules accepted a one-card deck")
+	}
+}
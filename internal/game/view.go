@@ -0,0 +1,155 @@
+package game
+
+import (
+	"io"
+	"sort"
+
+	"GoGame/internal/card"
+	"GoGame/internal/player"
+)
+
+// PlayerState is one player as a seat may see them. Hand is only filled in
+// for the player the view belongs to; the opponent's hand and both decks
+// are reduced to their sizes. The field and discard piles are public.
+type PlayerState struct {
+	Name      string      `json:"name"`
+	Health    int         `json:"health"`
+	MaxHealth int         `json:"max_health"`
+	Mana      int         `json:"mana"`
+	MaxMana   int         `json:"max_mana"`
+	Armor     int         `json:"armor"`
+	Score     int         `json:"score"`
+	Hand      []card.Card `json:"hand,omitempty"`
+	HandSize  int         `json:"hand_size"`
+	DeckSize  int         `json:"deck_size"`
+	Board     []card.Card `json:"board,omitempty"` // units on the player's half of the field
+	Discard   []card.Card `json:"discard,omitempty"`
+	Fatigue   int         `json:"fatigue,omitempty"` // damage of the last fatigue draw
+	DeckedOut bool        `json:"decked_out,omitempty"`
+
+	// Equipment and modifiers are public, as are the bonuses they give to
+	// a zero base amount; percent modifiers scale the actual amount
+	Ring         *player.Item      `json:"ring,omitempty"`
+	Necklace     *player.Item      `json:"necklace,omitempty"`
+	Weapon       *player.Item      `json:"weapon,omitempty"`
+	Modifiers    []player.Modifier `json:"modifiers,omitempty"`
+	DamageBonus  int               `json:"damage_bonus,omitempty"`
+	HealingBonus int               `json:"healing_bonus,omitempty"`
+	ManaBonus    int               `json:"mana_bonus,omitempty"`
+
+	// snapshot lets Simulate rebuild the player, including modifiers
+	snapshot player.Player
+	// deck is the viewer's own remaining deck in a fixed order, so it
+	// tells what is left but not the order it is drawn in
+	deck []card.Card
+}
+
+// View is the game as seen from one seat, with everything that seat may
+// not know left out. It is a copy: changing it has no effect on the game.
+// Anything shown to a client, over the network or on a shared screen,
+// should be built from a View rather than from the Game itself.
+type View struct {
+	Turn     int         `json:"turn"`
+	Phase    GamePhase   `json:"phase"`
+	MyTurn   bool        `json:"my_turn"`
+	Rules    Rules       `json:"rules"`
+	Self     PlayerState `json:"self"`
+	Opponent PlayerState `json:"opponent"`
+}
+
+// ViewFor returns the game as seen by p.
+func (g *Game) ViewFor(p *player.Player) View {
+	return View{
+		Turn:     g.TurnCount,
+		Phase:    g.CurrentPhase,
+		MyTurn:   g.CurrentPlayer == p,
+		Rules:    g.Rules,
+		Self:     g.playerState(p, true),
+		Opponent: g.playerState(g.Opponent(p), false),
+	}
+}
+
+func (g *Game) playerState(p *player.Player, withHand bool) PlayerState {
+	state := PlayerState{
+		Name:      p.Name,
+		Health:    p.Health,
+		MaxHealth: p.EffectiveMaxHealth(),
+		Mana:      p.Mana,
+		MaxMana:   p.EffectiveMaxMana(),
+		Armor:     p.EffectiveArmor(),
+		Score:     p.Score,
+		HandSize:  len(p.Hand),
+		DeckSize:  len(*g.DeckOf(p)),
+		Board:     g.FieldOf(p).Units(),
+		Discard:   append([]card.Card(nil), *g.DiscardOf(p)...),
+		snapshot:  copyPlayer(p),
+	}
+	seat := g.seatIndex(p)
+	state.Fatigue, state.DeckedOut = g.fatigueDamage[seat], g.deckedOut[seat]
+	state.Ring, state.Necklace, state.Weapon = state.snapshot.Ring, state.snapshot.Necklace, state.snapshot.Weapon
+	state.Modifiers = state.snapshot.Modifiers
+	state.DamageBonus, state.HealingBonus, state.ManaBonus = p.DamageBonus(0), p.HealingBonus(0), p.ManaBonus()
+	state.snapshot.Hand = nil
+	if withHand {
+		state.Hand = append([]card.Card(nil), p.Hand...)
+		state.snapshot.Hand = append([]card.Card(nil), p.Hand...)
+		state.deck = append([]card.Card(nil), *g.DeckOf(p)...)
+		sort.SliceStable(state.deck, func(i, j int) bool {
+			a, b := state.deck[i], state.deck[j]
+			return a.ID < b.ID || a.ID == b.ID && a.Name < b.Name
+		})
+	}
+	return state
+}
+
+// EventsFor returns the events from index since on as p may see them: the
+// cards the opponent drew or shuffled back during a mulligan are left out.
+func (g *Game) EventsFor(p *player.Player, since int) []Event {
+	if since < 0 || since > len(g.Events) {
+		since = len(g.Events)
+	}
+	events := make([]Event, 0, len(g.Events)-since)
+	for _, e := range g.Events[since:] {
+		e = e.clone()
+		if e.Player != p.Name && (e.Kind == EventDraw || e.Kind == EventMulligan) {
+			e.Cards, e.Indices = nil, nil
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func copyPlayer(p *player.Player) player.Player {
+	return *p.Clone()
+}
+
+// Simulate returns the view as it would be after the viewing player took
+// action a. Only the viewer's own known cards are used; hidden information
+// such as deck order is not needed because playing a card never draws.
+func (v View) Simulate(a Action) (View, error) {
+	g := v.Determinize(nil).base
+	g.Output = io.Discard
+	if err := g.Apply(&g.Player1, a); err != nil {
+		return v, err
+	}
+
+	next := g.ViewFor(&g.Player1)
+	next.Self.DeckSize = v.Self.DeckSize
+	next.Opponent.HandSize = v.Opponent.HandSize
+	next.Opponent.DeckSize = v.Opponent.DeckSize
+	return next, nil
+}
+
+// LegalActions lists what the viewing player may do right now.
+func (v View) LegalActions() []Action {
+	if !v.MyTurn || v.Phase != PlayPhase {
+		return nil
+	}
+	var actions []Action
+	for i, c := range v.Self.Hand {
+		if c.Cost <= v.Self.Mana {
+			actions = append(actions, Action{Kind: ActionPlayCard, CardIndex: i})
+		}
+	}
+	return append(actions, Action{Kind: ActionEndTurn})
+}
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"GoGame/internal/card"
+)
+
+func TestViewForHidesOpponentHand(t *testing.T) {
+	g := newTestGame(t)
+	v := g.ViewFor(&g.Player1)
+
+	if !card.Equal(v.Self.Hand, g.Player1.Hand) {
+		t.Fatal("own hand is missing from the view")
+	}
+	if len(v.Opponent.Hand) != 0 || v.Opponent.HandSize != len(g.Player2.Hand) {
+		t.Fatalf("opponent hand: %d cards shown, size %d", len(v.Opponent.Hand), v.Opponent.HandSize)
+	}
+	if v.Self.DeckSize != len(g.Field.PlayerDeck) || v.Opponent.DeckSize != len(g.Field.OpponentDeck) {
+		t.Fatal("deck sizes are wrong")
+	}
+	if !card.Equal(v.Opponent.Discard, g.Field.OpponentDiscard) || !card.Equal(v.Opponent.Board, g.Field.OpponentField.Units()) {
+		t.Fatal("public cards are missing from the view")
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), `"hand":`); n != 1 {
+		t.Fatalf("JSON view has %d hands, want only the viewer's", n)
+	}
+
+	var decoded View
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Phase != v.Phase || !card.Equal(decoded.Self.Hand, v.Self.Hand) || decoded.Rules.TurnLimit != v.Rules.TurnLimit {
+		t.Fatal("view does not survive a JSON round trip")
+	}
+}
+
+func TestEventsForHidesOpponentDraws(t *testing.T) {
+	g := newTestGame(t)
+	for _, e := range g.EventsFor(&g.Player1, 0) {
+		if e.Kind == EventDraw && e.Player == g.Player2.Name && len(e.Cards) > 0 {
+			t.Fatalf("opponent draw shown: %+v", e)
+		}
+	}
+	if n := len(g.EventsFor(&g.Player1, len(g.Events)-2)); n != 2 {
+		t.Fatalf("got %d events since the last two, want 2", n)
+	}
+}
@@ -0,0 +1,534 @@
+// Package lobby is a matchmaking front end for a netplay server. Players
+// list the open games, create one with a ruleset, join one by its code or
+// ask for a quick match, and confirm that they are ready before the game is
+// hosted on the netplay server.
+//
+// # Protocol
+//
+// The lobby speaks the JSON-lines protocol of package netplay on the same
+// connection that later plays the game:
+//
+//	{"type":"list"}                                                       answered with games
+//	{"type":"create","name":"Alice","title":"Friday","ruleset":"legacy"}  answered with table
+//	{"type":"join","name":"Bob","code":"K7QF"}                            answered with table
+//	{"type":"quick","name":"Bob"}                                         table once matched
+//	{"type":"ready"}                                                      answers a ready request
+//	{"type":"resume",...}                                                 as in netplay
+//
+//	{"type":"games","games":[{"code":"K7QF","title":"Friday","ruleset":"legacy","players":["Alice"],"seats":1}]}
+//	{"type":"table","table":{...}}                                        the table the player sits at changed
+//	{"type":"request","request":"ready"}                                  the table is full
+//
+// When a table is full every player is asked whether they are ready and has
+// Service.ReadyTime to answer. If everyone is, the game starts with the
+// netplay welcome. Otherwise the players who did not answer are sent an
+// error and disconnected, and the table opens again for the others. A quick
+// match pairs two players asking for one, or seats a computer opponent
+// after Service.QuickWait. A player who disconnects leaves their table.
+package lobby
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"GoGame/internal/game"
+	"GoGame/internal/netplay"
+)
+
+// DefaultRuleset is used by quick matches and by games created without a
+// ruleset.
+const DefaultRuleset = "default"
+
+// AIName is the name of the computer opponent of a quick match.
+const AIName = "Computer"
+
+// Rulesets returns the built-in rulesets by name.
+func Rulesets() map[string]game.Rules {
+	return map[string]game.Rules{
+		DefaultRuleset: game.DefaultRules(),
+		"legacy":       game.LegacyRules(),
+	}
+}
+
+// Service runs a lobby and hosts its games on Server.
+type Service struct {
+	Server   *netplay.Server
+	Store    Store
+	Rulesets map[string]game.Rules
+	// AI creates the computer opponent of a quick match that found no
+	// human opponent within QuickWait; nil always waits for a human.
+	AI        func() game.Controller
+	QuickWait time.Duration
+	// ReadyTime is how long players have to confirm they are ready.
+	ReadyTime time.Duration
+
+	mu     sync.Mutex
+	tables map[string]*table
+	quick  *member // waits for a quick match
+}
+
+// NewService creates a lobby with the built-in rulesets that hosts its games
+// on server and keeps its tables in store.
+func NewService(server *netplay.Server, store Store) *Service {
+	return &Service{
+		Server:    server,
+		Store:     store,
+		Rulesets:  Rulesets(),
+		QuickWait: 10 * time.Second,
+		ReadyTime: 30 * time.Second,
+	}
+}
+
+// member is a player in the lobby, or the computer opponent if ai is set.
+type member struct {
+	conn *netplay.Conn
+	ai   game.Controller
+
+	// guarded by Service.mu
+	name  string
+	code  string      // table the member sits at
+	timer *time.Timer // seats the AI while waiting for a quick match
+}
+
+// stopWaiting stops the quick match timer of m. Service.mu must be held.
+func (m *member) stopWaiting() {
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+}
+
+// table holds the members of a stored Table.
+type table struct {
+	members []*member
+	ready   chan readiness // set during the ready check
+	done    chan struct{}  // closed when the ready check has its answers
+
+	// watchers are the confirmed members whose connections are watched
+	// until done is closed
+	watchers sync.WaitGroup
+}
+
+type readiness struct {
+	m  *member
+	ok bool
+}
+
+// Serve accepts connections until l is closed.
+func (s *Service) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serve(&member{conn: netplay.NewConn(conn)})
+	}
+}
+
+// serve answers the lobby messages of m until its game is about to start.
+func (s *Service) serve(m *member) {
+	for {
+		msg, err := m.conn.Receive()
+		if err != nil {
+			s.leave(m)
+			m.conn.Close()
+			return
+		}
+
+		switch msg.Type {
+		case netplay.TypeResume:
+			s.leave(m)
+			s.Server.Resume(m.conn, msg)
+			return
+		case netplay.TypeList:
+			var games []netplay.Listing
+			if games, err = s.list(); err == nil {
+				m.conn.Send(netplay.Message{Type: netplay.TypeGames, Games: games})
+			}
+		case netplay.TypeCreate:
+			err = s.create(m, msg)
+		case netplay.TypeJoin:
+			err = s.join(m, msg)
+		case netplay.TypeQuick:
+			err = s.quickMatch(m, msg.Name)
+		case netplay.TypeReady:
+			if tab := s.ready(m); tab != nil {
+				// the ready check takes over the connection; until it has
+				// everyone's answer a dropped connection leaves the table
+				if err := m.conn.Wait(tab.done); err != nil {
+					s.leave(m)
+					m.conn.Close()
+				}
+				tab.watchers.Done()
+				return
+			}
+			err = errors.New("no ready check is running")
+		default:
+			err = fmt.Errorf("unexpected %q message", msg.Type)
+		}
+		if err != nil {
+			m.conn.Send(netplay.Message{Type: netplay.TypeError, Error: err.Error()})
+		}
+	}
+}
+
+func (s *Service) list() ([]netplay.Listing, error) {
+	tables, err := s.Store.List()
+	if err != nil {
+		return nil, err
+	}
+	var games []netplay.Listing
+	for _, t := range tables {
+		if t.Open() {
+			games = append(games, listing(t))
+		}
+	}
+	return games, nil
+}
+
+func listing(t Table) netplay.Listing {
+	return netplay.Listing{
+		Code:    t.Code,
+		Title:   t.Title,
+		Ruleset: t.Ruleset,
+		Players: t.Players,
+		Seats:   2 - len(t.Players),
+	}
+}
+
+func (s *Service) create(m *member, msg netplay.Message) error {
+	ruleset := msg.Ruleset
+	if ruleset == "" {
+		ruleset = DefaultRuleset
+	}
+	if _, ok := s.Rulesets[ruleset]; !ok {
+		return fmt.Errorf("unknown ruleset %q", ruleset)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m.code != "" {
+		return errors.New("already at a table")
+	}
+	m.name = playerName(msg.Name)
+	title := msg.Title
+	if title == "" {
+		title = m.name + "'s game"
+	}
+	return s.seat(Table{Title: title, Ruleset: ruleset}, m)
+}
+
+func (s *Service) join(m *member, msg netplay.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m.code != "" {
+		return errors.New("already at a table")
+	}
+	t, ok, err := s.Store.Get(msg.Code)
+	if err != nil {
+		return err
+	}
+	if !ok || s.tables[msg.Code] == nil {
+		return fmt.Errorf("no game with code %q", msg.Code)
+	}
+	if !t.Open() {
+		return fmt.Errorf("game %s is full", msg.Code)
+	}
+
+	m.name = playerName(msg.Name)
+	tab := s.tables[t.Code]
+	tab.members = append(tab.members, m)
+	m.code = t.Code
+	return s.save(t.Code)
+}
+
+func (s *Service) quickMatch(m *member, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m.code != "" {
+		return errors.New("already at a table")
+	}
+	m.name = playerName(name)
+	if s.quick == nil || s.quick == m {
+		s.quick = m
+		if s.AI != nil {
+			m.stopWaiting()
+			m.timer = time.AfterFunc(s.QuickWait, func() { s.fallback(m) })
+		}
+		return nil
+	}
+	opponent := s.quick
+	s.quick = nil
+	return s.seat(Table{Title: "Quick match", Ruleset: DefaultRuleset}, opponent, m)
+}
+
+// fallback seats a computer opponent for m if it still waits for a quick
+// match.
+func (s *Service) fallback(m *member) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.quick != m {
+		return
+	}
+	s.quick = nil
+	m.timer = nil
+	ai := &member{ai: s.AI(), name: AIName}
+	if err := s.seat(Table{Title: "Quick match", Ruleset: DefaultRuleset}, m, ai); err != nil {
+		m.conn.Send(netplay.Message{Type: netplay.TypeError, Error: err.Error()})
+	}
+}
+
+// seat opens a new table for t with the members sitting at it. s.mu must be
+// held.
+func (s *Service) seat(t Table, members ...*member) error {
+	code, err := s.newCode()
+	if err != nil {
+		return err
+	}
+	t.Code = code
+	if err := s.Store.Put(t); err != nil {
+		return err
+	}
+	if s.tables == nil {
+		s.tables = make(map[string]*table)
+	}
+	s.tables[code] = &table{members: members}
+	for _, m := range members {
+		m.code = code
+		if s.quick == m {
+			s.quick = nil
+		}
+		m.stopWaiting()
+	}
+	return s.save(code)
+}
+
+// newCode returns an unused table code. s.mu must be held.
+func (s *Service) newCode() (string, error) {
+	const letters = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	for {
+		b := make([]byte, 4)
+		for i := range b {
+			b[i] = letters[rand.Intn(len(letters))]
+		}
+		_, taken, err := s.Store.Get(string(b))
+		if err != nil || !taken {
+			return string(b), err
+		}
+	}
+}
+
+// save stores the players of a table, tells them and starts the ready
+// check once the table is full. s.mu must be held.
+func (s *Service) save(code string) error {
+	t, _, err := s.Store.Get(code)
+	if err != nil {
+		return err
+	}
+	tab := s.tables[code]
+	t.Players = t.Players[:0]
+	for _, m := range tab.members {
+		t.Players = append(t.Players, m.name)
+	}
+	if err := s.Store.Put(t); err != nil {
+		return err
+	}
+
+	l := listing(t)
+	for _, m := range tab.members {
+		if m.conn != nil {
+			m.conn.Send(netplay.Message{Type: netplay.TypeTable, Table: &l})
+		}
+	}
+	if len(tab.members) == 2 && tab.ready == nil {
+		go s.check(code)
+	}
+	return nil
+}
+
+// leave takes m from its table or from the quick match queue.
+func (s *Service) leave(m *member) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.quick == m {
+		s.quick = nil
+	}
+	m.stopWaiting()
+	tab := s.tables[m.code]
+	if tab == nil {
+		return
+	}
+	code := m.code
+	m.code = ""
+	tab.remove(m)
+	if tab.ready != nil {
+		// the ready check cleans up
+		tab.ready <- readiness{m: m}
+		return
+	}
+	s.tidy(code)
+}
+
+func (t *table) remove(m *member) {
+	for i, other := range t.members {
+		if other == m {
+			t.members = append(t.members[:i], t.members[i+1:]...)
+			return
+		}
+	}
+}
+
+// humans returns the members that are players on a connection.
+func (t *table) humans() []*member {
+	var humans []*member
+	for _, m := range t.members {
+		if m.conn != nil {
+			humans = append(humans, m)
+		}
+	}
+	return humans
+}
+
+// tidy closes a table nobody sits at any more, or saves it. s.mu must be
+// held.
+func (s *Service) tidy(code string) {
+	tab := s.tables[code]
+	if len(tab.humans()) == 0 {
+		for _, m := range tab.members {
+			m.code = ""
+		}
+		delete(s.tables, code)
+		s.Store.Delete(code)
+		return
+	}
+	s.save(code)
+}
+
+// ready confirms m for the running ready check of its table and returns
+// the table, or nil if no check is waiting for answers. The caller must
+// watch the connection until the table's done is closed and then call
+// watchers.Done.
+func (s *Service) ready(m *member) *table {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tab := s.tables[m.code]
+	if tab == nil || tab.ready == nil {
+		return nil
+	}
+	select {
+	case <-tab.done:
+		return nil
+	default:
+	}
+	tab.watchers.Add(1)
+	tab.ready <- readiness{m: m, ok: true}
+	return tab
+}
+
+// check asks everyone at a full table whether they are ready and starts
+// the game if they all are.
+func (s *Service) check(code string) {
+	s.mu.Lock()
+	tab := s.tables[code]
+	if tab == nil || tab.ready != nil || len(tab.members) < 2 {
+		s.mu.Unlock()
+		return
+	}
+	humans := tab.humans()
+	// room for a confirmation and a leave from everyone
+	ready := make(chan readiness, 2*len(humans))
+	tab.ready = ready
+	tab.done = make(chan struct{})
+	for _, m := range humans {
+		m.conn.Send(netplay.Message{Type: netplay.TypeRequest, Request: netplay.TypeReady})
+	}
+	s.mu.Unlock()
+
+	confirmed := make(map[*member]bool)
+	timeout := time.After(s.ReadyTime)
+	ok := true
+	for ok && len(confirmed) < len(humans) {
+		select {
+		case r := <-ready:
+			if r.ok {
+				confirmed[r.m] = true
+			} else {
+				ok = false
+			}
+		case <-timeout:
+			ok = false
+		}
+	}
+
+	// stop watching the confirmed connections before handing them on
+	s.mu.Lock()
+	close(tab.done)
+	s.mu.Unlock()
+	tab.watchers.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tab.ready = nil
+	if ok && len(tab.members) < 2 {
+		// a confirmed player left after the last answer
+		ok = false
+	}
+	if ok {
+		s.start(code)
+		return
+	}
+	for _, m := range tab.humans() {
+		if confirmed[m] {
+			go s.serve(m)
+			continue
+		}
+		tab.remove(m)
+		m.code = ""
+		m.conn.Send(netplay.Message{Type: netplay.TypeError, Error: "not ready in time"})
+		m.conn.Close()
+	}
+	s.tidy(code)
+}
+
+// start hosts the game of a table whose players are ready. s.mu must be
+// held.
+func (s *Service) start(code string) {
+	tab := s.tables[code]
+	t, _, err := s.Store.Get(code)
+	if err == nil {
+		t.Started = true
+		err = s.Store.Put(t)
+	}
+	if err != nil {
+		for _, m := range tab.humans() {
+			m.conn.Send(netplay.Message{Type: netplay.TypeError, Error: err.Error()})
+			m.conn.Close()
+		}
+		delete(s.tables, code)
+		return
+	}
+
+	var seats [2]netplay.Seat
+	for i, m := range tab.members {
+		seats[i] = netplay.Seat{Name: m.name, Conn: m.conn, Controller: m.ai}
+	}
+	rules := s.Rulesets[t.Ruleset]
+	go func() {
+		s.Server.Host(seats, rules)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.tables, code)
+		s.Store.Delete(code)
+	}()
+}
+
+func playerName(name string) string {
+	if name == "" {
+		return "Player"
+	}
+	return name
+}
@@ -0,0 +1,329 @@
+package lobby
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"GoGame/internal/game"
+	"GoGame/internal/netplay"
+)
+
+// endTurns ends every turn it is asked to play.
+type endTurns struct{}
+
+func (endTurns) Mulligan(v game.View) []int         { return nil }
+func (endTurns) NextAction(v game.View) game.Action { return game.Action{Kind: game.ActionEndTurn} }
+
+// startLobby serves a lobby on a localhost port until the test ends.
+func startLobby(t *testing.T, configure func(*Service)) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	server := netplay.NewServer()
+	server.Seed = 1
+	s := NewService(server, NewMemoryStore())
+	for name, rules := range s.Rulesets {
+		rules.TurnLimit = 4
+		s.Rulesets[name] = rules
+	}
+	s.ReadyTime = time.Second
+	if configure != nil {
+		configure(s)
+	}
+	go s.Serve(l)
+	return l.Addr().String()
+}
+
+func connect(t *testing.T, addr string) *netplay.Client {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	c := netplay.NewClient(conn)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func send(t *testing.T, c *netplay.Client, msg netplay.Message) {
+	t.Helper()
+	if err := c.Send(msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// expect reads the next message and checks its type.
+func expect(t *testing.T, c *netplay.Client, typ string) netplay.Message {
+	t.Helper()
+	msg, err := c.Receive()
+	if err != nil {
+		t.Fatalf("waiting for %s: %v", typ, err)
+	}
+	if msg.Type != typ {
+		t.Fatalf("got %+v, want a %s message", msg, typ)
+	}
+	return msg
+}
+
+// finish ends every turn until the game is over and returns the result.
+func finish(t *testing.T, c *netplay.Client) netplay.Result {
+	t.Helper()
+	for {
+		msg, err := c.Receive()
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case msg.Type == netplay.TypeResult:
+			return *msg.Result
+		case msg.Type == netplay.TypeRequest && msg.Request == netplay.TypeMulligan:
+			c.Mulligan(nil)
+		case msg.Type == netplay.TypeRequest && msg.Request == netplay.TypeAction:
+			c.Act(netplay.Action{Kind: "end"})
+		}
+	}
+}
+
+func TestCreateListJoinAndPlay(t *testing.T) {
+	addr := startLobby(t, nil)
+	alice, bob := connect(t, addr), connect(t, addr)
+
+	send(t, alice, netplay.Message{Type: netplay.TypeCreate, Name: "Alice", Title: "Friday", Ruleset: "legacy"})
+	table := expect(t, alice, netplay.TypeTable).Table
+	if table.Code == "" || table.Seats != 1 || table.Ruleset != "legacy" {
+		t.Fatalf("created %+v", table)
+	}
+
+	send(t, bob, netplay.Message{Type: netplay.TypeList})
+	games := expect(t, bob, netplay.TypeGames).Games
+	if len(games) != 1 || games[0].Code != table.Code || games[0].Title != "Friday" {
+		t.Fatalf("listed %+v", games)
+	}
+	send(t, bob, netplay.Message{Type: netplay.TypeJoin, Name: "Bob", Code: "NOPE"})
+	expect(t, bob, netplay.TypeError)
+	send(t, bob, netplay.Message{Type: netplay.TypeJoin, Name: "Bob", Code: table.Code})
+
+	for i, c := range []*netplay.Client{alice, bob} {
+		full := expect(t, c, netplay.TypeTable).Table
+		if len(full.Players) != 2 || full.Seats != 0 {
+			t.Fatalf("client %d: table %+v", i+1, full)
+		}
+		if req := expect(t, c, netplay.TypeRequest); req.Request != netplay.TypeReady {
+			t.Fatalf("client %d: request %q", i+1, req.Request)
+		}
+	}
+	send(t, alice, netplay.Message{Type: netplay.TypeReady})
+	send(t, bob, netplay.Message{Type: netplay.TypeReady})
+
+	for i, name := range []string{"Alice", "Bob"} {
+		c := []*netplay.Client{alice, bob}[i]
+		welcome := expect(t, c, netplay.TypeWelcome)
+		if welcome.Name != name || welcome.Seat != i+1 {
+			t.Fatalf("welcome %+v", welcome)
+		}
+	}
+
+	done := make(chan netplay.Result, 1)
+	go func() { done <- finish(t, bob) }()
+	if r := finish(t, alice); r.Reason != game.EndTurnLimit.String() || r != <-done {
+		t.Fatalf("result %+v", r)
+	}
+
+	// the finished game leaves the lobby
+	carol := connect(t, addr)
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		send(t, carol, netplay.Message{Type: netplay.TypeList})
+		if games := expect(t, carol, netplay.TypeGames).Games; len(games) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("finished game is still listed")
+		}
+	}
+}
+
+func TestQuickMatchPairsPlayers(t *testing.T) {
+	addr := startLobby(t, func(s *Service) {
+		s.AI = func() game.Controller { return endTurns{} }
+		s.QuickWait = time.Minute
+	})
+	alice, bob := connect(t, addr), connect(t, addr)
+	send(t, alice, netplay.Message{Type: netplay.TypeQuick, Name: "Alice"})
+	time.Sleep(20 * time.Millisecond)
+	send(t, bob, netplay.Message{Type: netplay.TypeQuick, Name: "Bob"})
+
+	for _, c := range []*netplay.Client{alice, bob} {
+		table := expect(t, c, netplay.TypeTable).Table
+		if len(table.Players) != 2 || table.Players[0] != "Alice" || table.Players[1] != "Bob" {
+			t.Fatalf("quick match %+v", table)
+		}
+	}
+}
+
+func TestQuickMatchFallsBackToAI(t *testing.T) {
+	addr := startLobby(t, func(s *Service) {
+		s.AI = func() game.Controller { return endTurns{} }
+		s.QuickWait = 50 * time.Millisecond
+	})
+	alice := connect(t, addr)
+	send(t, alice, netplay.Message{Type: netplay.TypeQuick, Name: "Alice"})
+
+	table := expect(t, alice, netplay.TypeTable).Table
+	if len(table.Players) != 2 || table.Players[1] != AIName {
+		t.Fatalf("quick match %+v", table)
+	}
+	expect(t, alice, netplay.TypeRequest)
+	send(t, alice, netplay.Message{Type: netplay.TypeReady})
+	expect(t, alice, netplay.TypeWelcome)
+
+	if r := finish(t, alice); r.Winner == AIName || r.Reason != game.EndTurnLimit.String() {
+		t.Fatalf("result %+v against an AI that only ends turns", r)
+	}
+}
+
+func TestReadyCheckTimesOut(t *testing.T) {
+	addr := startLobby(t, func(s *Service) { s.ReadyTime = 100 * time.Millisecond })
+	alice, bob := connect(t, addr), connect(t, addr)
+	send(t, alice, netplay.Message{Type: netplay.TypeCreate, Name: "Alice"})
+	code := expect(t, alice, netplay.TypeTable).Table.Code
+	send(t, bob, netplay.Message{Type: netplay.TypeJoin, Name: "Bob", Code: code})
+	expect(t, bob, netplay.TypeTable)
+	expect(t, alice, netplay.TypeTable)
+	expect(t, alice, netplay.TypeRequest)
+	send(t, alice, netplay.Message{Type: netplay.TypeReady})
+
+	// Bob never answers
+	expect(t, bob, netplay.TypeRequest)
+	expect(t, bob, netplay.TypeError)
+	if _, err := bob.Receive(); err == nil {
+		t.Fatal("Bob is still connected")
+	}
+
+	table := expect(t, alice, netplay.TypeTable).Table
+	if table.Code != code || len(table.Players) != 1 || table.Seats != 1 {
+		t.Fatalf("table after the failed check %+v", table)
+	}
+	// Alice is back in the lobby
+	send(t, alice, netplay.Message{Type: netplay.TypeList})
+	if games := expect(t, alice, netplay.TypeGames).Games; len(games) != 1 || games[0].Code != code {
+		t.Fatalf("listed %+v", games)
+	}
+}
+
+func TestQuickMatchKeepsOneTimer(t *testing.T) {
+	s := NewService(netplay.NewServer(), NewMemoryStore())
+	s.AI = func() game.Controller { return endTurns{} }
+	s.QuickWait = time.Minute
+	m := &member{}
+
+	if err := s.quickMatch(m, "Alice"); err != nil {
+		t.Fatal(err)
+	}
+	first := m.timer
+	if err := s.quickMatch(m, "Alice"); err != nil {
+		t.Fatal(err)
+	}
+	if m.timer == nil || m.timer == first {
+		t.Fatal("asking again did not restart the wait")
+	}
+	if first.Stop() {
+		t.Fatal("the first timer is still running")
+	}
+
+	second := m.timer
+	s.leave(m)
+	if s.quick != nil || m.timer != nil || second.Stop() {
+		t.Fatal("leaving kept the quick match timer")
+	}
+}
+
+func TestReadyCheckSeesDrop(t *testing.T) {
+	addr := startLobby(t, func(s *Service) { s.ReadyTime = time.Minute })
+	alice, bob := connect(t, addr), connect(t, addr)
+	send(t, alice, netplay.Message{Type: netplay.TypeCreate, Name: "Alice"})
+	code := expect(t, alice, netplay.TypeTable).Table.Code
+	send(t, bob, netplay.Message{Type: netplay.TypeJoin, Name: "Bob", Code: code})
+	expect(t, bob, netplay.TypeTable)
+	expect(t, bob, netplay.TypeRequest)
+	expect(t, alice, netplay.TypeTable)
+	expect(t, alice, netplay.TypeRequest)
+
+	send(t, bob, netplay.Message{Type: netplay.TypeReady})
+	time.Sleep(20 * time.Millisecond)
+	bob.Close()
+
+	// Alice hears about it long before the ready time runs out
+	started := time.Now()
+	expect(t, alice, netplay.TypeError)
+	if waited := time.Since(started); waited > 5*time.Second {
+		t.Fatalf("drop noticed after %v", waited)
+	}
+	carol := connect(t, addr)
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		send(t, carol, netplay.Message{Type: netplay.TypeList})
+		if games := expect(t, carol, netplay.TypeGames).Games; len(games) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("table is still listed after everyone left")
+		}
+	}
+}
+
+func TestReadyCheckOutlastsStrayMessages(t *testing.T) {
+	addr := startLobby(t, func(s *Service) { s.ReadyTime = time.Minute })
+	alice, bob := connect(t, addr), connect(t, addr)
+	send(t, alice, netplay.Message{Type: netplay.TypeCreate, Name: "Alice"})
+	code := expect(t, alice, netplay.TypeTable).Table.Code
+	send(t, bob, netplay.Message{Type: netplay.TypeJoin, Name: "Bob", Code: code})
+	expect(t, bob, netplay.TypeTable)
+	expect(t, bob, netplay.TypeRequest)
+	expect(t, alice, netplay.TypeTable)
+	expect(t, alice, netplay.TypeRequest)
+
+	// a message while the ready check runs is refused, and a drop after
+	// it is still noticed
+	send(t, bob, netplay.Message{Type: netplay.TypeReady})
+	send(t, bob, netplay.Message{Type: netplay.TypeList})
+	expect(t, bob, netplay.TypeError)
+	send(t, bob, netplay.Message{Type: netplay.TypeList})
+	expect(t, bob, netplay.TypeError)
+	bob.Close()
+
+	started := time.Now()
+	expect(t, alice, netplay.TypeError)
+	if waited := time.Since(started); waited > 5*time.Second {
+		t.Fatalf("drop noticed after %v", waited)
+	}
+}
+
+func TestStrayMessageDoesNotReachTheGame(t *testing.T) {
+	addr := startLobby(t, nil)
+	alice, bob := connect(t, addr), connect(t, addr)
+	send(t, alice, netplay.Message{Type: netplay.TypeCreate, Name: "Alice"})
+	code := expect(t, alice, netplay.TypeTable).Table.Code
+	send(t, bob, netplay.Message{Type: netplay.TypeJoin, Name: "Bob", Code: code})
+	for _, c := range []*netplay.Client{alice, bob} {
+		expect(t, c, netplay.TypeTable)
+		expect(t, c, netplay.TypeRequest)
+	}
+
+	send(t, bob, netplay.Message{Type: netplay.TypeReady})
+	send(t, bob, netplay.Message{Type: netplay.TypeList})
+	expect(t, bob, netplay.TypeError)
+	send(t, alice, netplay.Message{Type: netplay.TypeReady})
+	expect(t, alice, netplay.TypeWelcome)
+	expect(t, bob, netplay.TypeWelcome)
+
+	done := make(chan netplay.Result, 1)
+	go func() { done <- finish(t, bob) }()
+	if r := finish(t, alice); r != <-done {
+		t.Fatalf("results %+v and %+v differ", r, <-done)
+	}
+}
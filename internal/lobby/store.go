@@ -0,0 +1,77 @@
+package lobby
+
+import (
+	"sort"
+	"sync"
+)
+
+// Table is a game in the lobby. Only what can be written down is stored;
+// the connections of the players stay with the Service.
+type Table struct {
+	Code    string
+	Title   string
+	Ruleset string
+	Players []string
+	Started bool
+}
+
+// Open reports whether the table waits for another player.
+func (t Table) Open() bool {
+	return !t.Started && len(t.Players) < 2
+}
+
+// Store keeps the tables of a lobby. MemoryStore is the default; a store
+// backed by a file or a database can be used instead.
+type Store interface {
+	Put(t Table) error
+	// Get returns the table with the code; ok is false if there is none.
+	Get(code string) (t Table, ok bool, err error)
+	Delete(code string) error
+	// List returns all tables ordered by code.
+	List() ([]Table, error)
+}
+
+// MemoryStore is a Store in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu     sync.Mutex
+	tables map[string]Table
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tables: make(map[string]Table)}
+}
+
+func (s *MemoryStore) Put(t Table) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.Players = append([]string(nil), t.Players...)
+	s.tables[t.Code] = t
+	return nil
+}
+
+func (s *MemoryStore) Get(code string) (Table, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tables[code]
+	t.Players = append([]string(nil), t.Players...)
+	return t, ok, nil
+}
+
+func (s *MemoryStore) Delete(code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tables, code)
+	return nil
+}
+
+func (s *MemoryStore) List() ([]Table, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tables := make([]Table, 0, len(s.tables))
+	for _, t := range s.tables {
+		t.Players = append([]string(nil), t.Players...)
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Code < tables[j].Code })
+	return tables, nil
+}
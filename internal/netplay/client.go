@@ -0,0 +1,90 @@
+package netplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// Client is the player side of a connection to a Server.
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// Dial connects to a server and says hello with the player's name.
+func Dial(addr, name string) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c := NewClient(conn)
+	if err := c.Send(Message{Type: TypeHello, Name: name}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Resume reconnects to the seat of a session token after a dropped
+// connection. since is the number of events the client has already
+// received; the server sends the rest.
+func Resume(addr, token string, since int) (*Client, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c := NewClient(conn)
+	if err := c.Send(Message{Type: TypeResume, Token: token, Since: since}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewClient wraps an established connection.
+func NewClient(conn net.Conn) *Client {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxLine)
+	return &Client{conn: conn, scanner: scanner, enc: json.NewEncoder(conn)}
+}
+
+// Send writes one message. It is safe to call from several goroutines.
+func (c *Client) Send(msg Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enc.Encode(msg)
+}
+
+// Receive waits for the next message from the server.
+func (c *Client) Receive() (Message, error) {
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return Message{}, err
+		}
+		return Message{}, fmt.Errorf("connection closed")
+	}
+	var msg Message
+	err := json.Unmarshal(c.scanner.Bytes(), &msg)
+	return msg, err
+}
+
+// Mulligan answers a mulligan request.
+func (c *Client) Mulligan(indices []int) error {
+	return c.Send(Message{Type: TypeMulligan, Indices: indices})
+}
+
+// Act answers an action request.
+func (c *Client) Act(a Action) error {
+	return c.Send(Message{Type: TypeAction, Action: &a})
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,77 @@
+// Package netplay hosts games over TCP. The server runs the game engine and
+// is the only authority over the game: clients send decisions and receive
+// what their seat is allowed to see, and every action is checked by
+// game.Game exactly as in a local game.
+//
+// # Protocol
+//
+// Each message is a single JSON object on its own line. Every message has a
+// "type"; the other fields depend on it.
+//
+// Client to server:
+//
+//	{"type":"hello","name":"Alice"}                               first message, name is optional
+//	{"type":"resume","token":"9f2c...","since":42}                first message instead of hello
+//	{"type":"mulligan","indices":[0,3]}                           answers a mulligan request
+//	{"type":"action","action":{"kind":"play","card":2}}           answers an action request
+//	{"type":"action","action":{"kind":"end"}}
+//	{"type":"action","action":{"kind":"concede"}}
+//
+// Server to client:
+//
+//	{"type":"welcome","seat":1,"name":"Alice","token":"9f2c..."}  seat is 1 or 2
+//	{"type":"state","state":{...}}                                game.View of the client's seat
+//	{"type":"event","event":{...}}                                a new game.Event, from game.EventsFor
+//	{"type":"request","request":"mulligan"}                       the server waits for a decision
+//	{"type":"request","request":"action"}
+//	{"type":"play","play":{...}}                                  game.PlayResult of a card just played
+//	{"type":"error","error":"no card at that position"}           the last message was rejected
+//	{"type":"result","result":{"winner":"Alice","reason":"health"}}
+//
+// A game starts once two clients have said hello. The server sends state
+// and new events to both clients whenever the game changes, and a request
+// to the client whose decision it is. A client answers every request with
+// exactly one message of the requested type; anything else is answered with
+// an error. An accepted card play is followed by a play message to both
+// clients that explains the damage and healing it caused; a rejected action
+// is followed by a new action request. After the result, which has an empty
+// winner for a tie, the server closes the connection.
+//
+// # Reconnecting
+//
+// The welcome carries a session token. A client whose connection drops can
+// open a new one and send resume with the token and the number of events it
+// has received. The server answers with the welcome again, the events the
+// client missed, the current state and the request it still has to answer,
+// if any. The seat is held for Server.Grace; a client that has not resumed
+// by then concedes at its next decision. Server.TurnTime, if set, is
+// paused while a client is away; a client that runs out of time keeps its
+// hand or ends its turn and is sent an "out of time" error.
+//
+// # Verifiable shuffles
+//
+// With Server.Verifiable the seats agree on the shuffle seed before the
+// game, so that the server cannot rig the decks, and check every draw
+// after it; package fairplay describes the scheme. The server asks for a
+// commitment to a secret, then for the secret itself, telling each client
+// the opponent's commitment and the digest of the deck lists:
+//
+//	{"type":"request","request":"commit"}
+//	{"type":"commit","commit":"5e1a..."}
+//	{"type":"request","request":"reveal","commit":"<opponent's>","digest":"<deck lists>"}
+//	{"type":"reveal","secret":"c09b..."}
+//
+// A secret that does not match its commitment stops the game with an
+// error. After the result the server sends {"type":"proof","proof":{...}},
+// a fairplay.Proof that the client checks with fairplay.Check. The server
+// still learns the seed, and so every card, before the first turn: the
+// scheme stops it from rigging the shuffle, not from peeking at it.
+//
+// Server.Serve pairs clients in the order they say hello. Package lobby
+// puts matchmaking in front of a Server and starts its games with
+// Server.Host.
+//
+// Clients only ever receive views and events projected for their own seat:
+// the opponent's hand and both decks appear as sizes, and the opponent's
+// draws and mulligans are sent without card names.
+package netplay
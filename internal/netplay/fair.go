@@ -0,0 +1,70 @@
+package netplay
+
+import (
+	"fmt"
+
+	"GoGame/internal/card"
+	"GoGame/internal/fairplay"
+	"GoGame/internal/game"
+)
+
+// agree runs the commit-reveal exchange of a verifiable game, seeds the
+// game and returns the secrets of both seats. Seats played in the server
+// get their secret from the server, committed to before any seat reveals;
+// the server learns the seed either way, see the fairplay package.
+// table.mu must be held.
+func (s *Server) agree(t *table) ([2]fairplay.Secret, error) {
+	g := t.g
+	var secrets [2]fairplay.Secret
+	var commits [2]fairplay.Hash
+	var remotes [2]*remote
+	for _, r := range t.remotes {
+		remotes[r.seat-1] = r
+	}
+
+	for i, r := range remotes {
+		if r == nil {
+			secrets[i] = fairplay.NewSecret()
+			commits[i] = secrets[i].Commit()
+			continue
+		}
+		msg, err := r.ask(TypeCommit)
+		if err == nil && msg.Commit == nil {
+			err = fmt.Errorf("commit message without a commitment")
+		}
+		if err != nil {
+			return secrets, fmt.Errorf("%s did not commit: %w", r.player.Name, err)
+		}
+		commits[i] = *msg.Commit
+	}
+
+	digest := fairplay.DeckDigest(fairplay.DeckIDs([2][]card.Card{g.DeckList(&g.Player1), g.DeckList(&g.Player2)}))
+	for i, r := range remotes {
+		if r == nil {
+			continue
+		}
+		opponent := commits[1-i]
+		msg, err := r.askWith(Message{Type: TypeRequest, Request: TypeReveal, Commit: &opponent, Digest: &digest})
+		if err == nil && msg.Secret == nil {
+			err = fmt.Errorf("reveal message without a secret")
+		}
+		if err != nil {
+			return secrets, fmt.Errorf("%s did not reveal: %w", r.player.Name, err)
+		}
+		if msg.Secret.Commit() != commits[i] {
+			return secrets, &fairplay.CheatError{Reason: fmt.Sprintf("%s revealed a secret that does not match its commitment", r.player.Name)}
+		}
+		secrets[i] = *msg.Secret
+	}
+	g.Seed(fairplay.Seed(secrets[0], secrets[1]))
+	return secrets, nil
+}
+
+// prove publishes the proof of a finished verifiable game.
+func (t *table) prove(g *game.Game, secrets [2]fairplay.Secret) {
+	proof := fairplay.NewProof(g, secrets)
+	t.proof = &proof
+	for _, r := range t.remotes {
+		r.send(Message{Type: TypeProof, Proof: t.proof})
+	}
+}
@@ -0,0 +1,123 @@
+package netplay
+
+import (
+	"fmt"
+
+	"GoGame/internal/fairplay"
+	"GoGame/internal/game"
+)
+
+// Message types.
+const (
+	TypeHello    = "hello"
+	TypeMulligan = "mulligan"
+	TypeAction   = "action"
+	TypeWelcome  = "welcome"
+	TypeState    = "state"
+	TypeEvent    = "event"
+	TypeRequest  = "request"
+	TypeError    = "error"
+	TypeResult   = "result"
+	TypePlay     = "play"
+	TypeResume   = "resume"
+)
+
+// Message types of the commit-reveal exchange, see Server.Verifiable.
+const (
+	TypeCommit = "commit"
+	TypeReveal = "reveal"
+	TypeProof  = "proof"
+)
+
+// Lobby message types, see package lobby.
+const (
+	TypeList   = "list"
+	TypeGames  = "games"
+	TypeCreate = "create"
+	TypeJoin   = "join"
+	TypeQuick  = "quick"
+	TypeTable  = "table"
+	TypeReady  = "ready"
+)
+
+// Message is one line of the protocol.
+type Message struct {
+	Type    string           `json:"type"`
+	Name    string           `json:"name,omitempty"`
+	Seat    int              `json:"seat,omitempty"`
+	Indices []int            `json:"indices,omitempty"`
+	Action  *Action          `json:"action,omitempty"`
+	State   *game.View       `json:"state,omitempty"`
+	Event   *game.Event      `json:"event,omitempty"`
+	Request string           `json:"request,omitempty"`
+	Error   string           `json:"error,omitempty"`
+	Result  *Result          `json:"result,omitempty"`
+	Play    *game.PlayResult `json:"play,omitempty"`
+	Token   string           `json:"token,omitempty"`
+	Since   int              `json:"since,omitempty"`
+	Code    string           `json:"code,omitempty"`
+	Title   string           `json:"title,omitempty"`
+	Ruleset string           `json:"ruleset,omitempty"`
+	Games   []Listing        `json:"games,omitempty"`
+	Table   *Listing         `json:"table,omitempty"`
+	Commit  *fairplay.Hash   `json:"commit,omitempty"`
+	Digest  *fairplay.Hash   `json:"digest,omitempty"`
+	Secret  *fairplay.Secret `json:"secret,omitempty"`
+	Proof   *fairplay.Proof  `json:"proof,omitempty"`
+}
+
+// Listing describes a game in a lobby.
+type Listing struct {
+	Code    string   `json:"code"`
+	Title   string   `json:"title"`
+	Ruleset string   `json:"ruleset"`
+	Players []string `json:"players"`
+	Seats   int      `json:"seats"` // open seats
+}
+
+// Action is a decision sent by a client. Kind is "play", "end" or "concede";
+// Card is the hand position of the card to play.
+type Action struct {
+	Kind string `json:"kind"`
+	Card int    `json:"card,omitempty"`
+}
+
+// WireAction converts an engine action for sending.
+func WireAction(a game.Action) Action {
+	switch a.Kind {
+	case game.ActionEndTurn:
+		return Action{Kind: "end"}
+	case game.ActionConcede:
+		return Action{Kind: "concede"}
+	default:
+		return Action{Kind: "play", Card: a.CardIndex}
+	}
+}
+
+// GameAction converts a received action for the engine.
+func (a Action) GameAction() (game.Action, error) {
+	switch a.Kind {
+	case "play":
+		return game.Action{Kind: game.ActionPlayCard, CardIndex: a.Card}, nil
+	case "end":
+		return game.Action{Kind: game.ActionEndTurn}, nil
+	case "concede":
+		return game.Action{Kind: game.ActionConcede}, nil
+	}
+	return game.Action{}, fmt.Errorf("unknown action kind %q", a.Kind)
+}
+
+// Result ends the game. Winner is empty for a tie.
+type Result struct {
+	Winner string `json:"winner"`
+	Reason string `json:"reason"`
+}
+
+// ResultOf converts an engine result for sending.
+func ResultOf(r game.Result) Result {
+	out := Result{Reason: r.Reason.String()}
+	if r.Winner != nil {
+		out.Winner = r.Winner.Name
+	}
+	return out
+}
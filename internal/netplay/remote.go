@@ -0,0 +1,445 @@
+package netplay
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"GoGame/internal/fairplay"
+	"GoGame/internal/game"
+	"GoGame/internal/player"
+)
+
+var (
+	errGone    = errors.New("player did not return")
+	errTimesUp = errors.New("out of time")
+)
+
+// Conn is the server side of a client connection.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+	enc     *json.Encoder
+}
+
+// NewConn wraps an accepted connection.
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{conn: conn, reader: bufio.NewReader(conn), enc: json.NewEncoder(conn)}
+}
+
+// waitPoll is how often Wait checks whether it should stop.
+const waitPoll = 50 * time.Millisecond
+
+// Wait holds the connection until stop is closed and returns the read
+// error if the client goes away first, nil otherwise. Messages the client
+// sends meanwhile are answered with an error and dropped, since nothing is
+// asked of it. Nothing else may read from c meanwhile.
+func (c *Conn) Wait(stop <-chan struct{}) error {
+	defer c.conn.SetReadDeadline(time.Time{})
+	for {
+		c.dropLines()
+		c.conn.SetReadDeadline(time.Now().Add(waitPoll))
+		_, err := c.reader.Peek(c.reader.Buffered() + 1)
+		var netErr net.Error
+		if err != nil && (!errors.As(err, &netErr) || !netErr.Timeout()) {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+	}
+}
+
+// dropLines discards the complete lines buffered by Wait, and a line too
+// long to buffer, answering each with an error.
+func (c *Conn) dropLines() {
+	for {
+		buffered, _ := c.reader.Peek(c.reader.Buffered())
+		end := bytes.IndexByte(buffered, '\n')
+		if end < 0 && len(buffered) < c.reader.Size() {
+			return
+		}
+		if end < 0 {
+			end = len(buffered) - 1
+		}
+		c.reader.Discard(end + 1)
+		c.Send(Message{Type: TypeError, Error: "nothing is asked of you yet"})
+	}
+}
+
+// Receive reads the next well-formed message. Malformed lines are answered
+// with an error and skipped.
+func (c *Conn) Receive() (Message, error) {
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return Message{}, err
+		}
+		var msg Message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			c.Send(Message{Type: TypeError, Error: "malformed message: " + err.Error()})
+			continue
+		}
+		return msg, nil
+	}
+}
+
+// readLine reads one line of at most maxLine bytes. It reads no further
+// than the end of the line, so Wait sees whatever the client sent next.
+func (c *Conn) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := c.reader.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > maxLine {
+			return nil, bufio.ErrTooLong
+		}
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && len(line) > 0:
+		case err != nil:
+			return nil, err
+		}
+		return bytes.TrimRight(line, "\r\n"), nil
+	}
+}
+
+// first waits for the hello or resume that opens a connection.
+func (c *Conn) first() (Message, error) {
+	for {
+		msg, err := c.Receive()
+		if err != nil {
+			return msg, fmt.Errorf("disconnected before hello: %w", err)
+		}
+		if msg.Type == TypeHello || msg.Type == TypeResume {
+			return msg, nil
+		}
+		c.Send(Message{Type: TypeError, Error: fmt.Sprintf("unexpected %q message", msg.Type)})
+	}
+}
+
+// Send writes one message. It is safe to call from several goroutines.
+func (c *Conn) Send(msg Message) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.enc.Encode(msg)
+}
+
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// table is a running game. The game goroutine holds mu except while it
+// waits for a client, so a returning client gets a consistent snapshot.
+type table struct {
+	mu      sync.Mutex
+	g       *game.Game
+	remotes []*remote       // seats played by clients
+	result  *Result         // set once the game is over
+	proof   *fairplay.Proof // sent after the result of a verifiable game
+}
+
+// remote is the controller of a seat played over the network. The seat
+// outlives its connections: a client that drops can resume it with the
+// session token until the grace period runs out.
+type remote struct {
+	server *Server
+	table  *table
+	seat   int
+	player *player.Player
+	token  string
+	played func() // sends the last play to both clients
+
+	// guarded by table.mu
+	sent     int           // events already sent
+	clockFor decision      // what the clock was last wound for
+	clock    time.Duration // time left for it
+
+	mu      sync.Mutex
+	conn    *Conn     // nil while disconnected
+	lost    time.Time // when the last connection was lost
+	pending string    // message type the server is waiting for
+	request Message   // the request for pending, sent again on resume
+	replies chan Message
+	changed chan struct{} // signalled when the connection is lost or replaced
+}
+
+func newRemote(s *Server, t *table, seat int, p *player.Player) *remote {
+	return &remote{
+		server:  s,
+		table:   t,
+		seat:    seat,
+		player:  p,
+		token:   newToken(),
+		replies: make(chan Message, 1),
+		changed: make(chan struct{}, 1),
+	}
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (r *remote) welcome() Message {
+	return Message{Type: TypeWelcome, Seat: r.seat, Name: r.player.Name, Token: r.token}
+}
+
+// attach makes c the connection of the seat and starts reading from it.
+func (r *remote) attach(c *Conn) {
+	r.mu.Lock()
+	old := r.conn
+	r.conn = c
+	r.mu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+	r.signal()
+	go r.read(c)
+}
+
+func (r *remote) signal() {
+	select {
+	case r.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (r *remote) read(c *Conn) {
+	defer r.detach(c)
+	for {
+		msg, err := c.Receive()
+		if err != nil {
+			return
+		}
+
+		r.mu.Lock()
+		expected := r.pending != "" && r.pending == msg.Type
+		if expected {
+			r.pending = ""
+		}
+		r.mu.Unlock()
+		if !expected {
+			c.Send(Message{Type: TypeError, Error: fmt.Sprintf("unexpected %q message", msg.Type)})
+			continue
+		}
+		r.replies <- msg
+	}
+}
+
+func (r *remote) detach(c *Conn) {
+	c.Close()
+	r.mu.Lock()
+	lost := r.conn == c
+	if lost {
+		r.conn = nil
+		r.lost = time.Now()
+	}
+	r.mu.Unlock()
+	if lost {
+		r.server.logf("%s disconnected", r.player.Name)
+		r.signal()
+	}
+}
+
+// resume attaches a returning client: it gets the events it missed, the
+// current state and the request it still has to answer. table.mu must be
+// held.
+func (r *remote) resume(c *Conn, since int) {
+	g := r.table.g
+	c.Send(r.welcome())
+	for _, e := range g.EventsFor(r.player, since) {
+		c.Send(Message{Type: TypeEvent, Event: &e})
+	}
+	r.sent = len(g.Events)
+	view := g.ViewFor(r.player)
+	c.Send(Message{Type: TypeState, State: &view})
+	if r.table.result != nil {
+		c.Send(Message{Type: TypeResult, Result: r.table.result})
+		if r.table.proof != nil {
+			c.Send(Message{Type: TypeProof, Proof: r.table.proof})
+		}
+		c.Close()
+		return
+	}
+
+	r.mu.Lock()
+	pending, request := r.pending, r.request
+	r.mu.Unlock()
+	if pending != "" {
+		c.Send(request)
+	}
+	r.attach(c)
+}
+
+func (r *remote) send(msg Message) {
+	r.mu.Lock()
+	c := r.conn
+	r.mu.Unlock()
+	if c != nil {
+		c.Send(msg)
+	}
+}
+
+func (r *remote) close() {
+	r.mu.Lock()
+	c := r.conn
+	r.mu.Unlock()
+	if c != nil {
+		c.Close()
+	}
+}
+
+// update sends the events the client has not seen yet and the current state.
+func (r *remote) update() {
+	g := r.table.g
+	for _, e := range g.EventsFor(r.player, r.sent) {
+		r.send(Message{Type: TypeEvent, Event: &e})
+	}
+	r.sent = len(g.Events)
+	view := g.ViewFor(r.player)
+	r.send(Message{Type: TypeState, State: &view})
+}
+
+// ask sends a request of the given type and waits for the answer, see
+// askWith.
+func (r *remote) ask(request string) (Message, error) {
+	return r.askWith(Message{Type: TypeRequest, Request: request})
+}
+
+// askWith sends a request and waits for the answer, with the game unlocked so
+// a dropped client can resume meanwhile. It fails with errGone if the
+// client stays away longer than the grace period and with errTimesUp when
+// the turn clock runs out; the clock only runs while the client is
+// connected.
+func (r *remote) askWith(request Message) (Message, error) {
+	if d := (decision{request.Request, r.table.g.TurnCount}); d != r.clockFor {
+		r.clockFor, r.clock = d, r.server.TurnTime
+	}
+	select {
+	case <-r.replies: // a late answer to a request that timed out
+	default:
+	}
+	r.mu.Lock()
+	r.pending, r.request = request.Request, request
+	r.mu.Unlock()
+	r.send(request)
+
+	r.table.mu.Unlock()
+	defer r.table.mu.Lock()
+
+	var deadline <-chan time.Time
+	var started time.Time
+	for {
+		r.mu.Lock()
+		connected, lost := r.conn != nil, r.lost
+		r.mu.Unlock()
+
+		var grace <-chan time.Time
+		if connected {
+			if deadline == nil && r.server.TurnTime > 0 {
+				started, deadline = time.Now(), time.After(r.clock)
+			}
+		} else {
+			if deadline != nil {
+				r.clock -= time.Since(started)
+				deadline = nil
+			}
+			left := r.server.Grace - time.Since(lost)
+			if left <= 0 {
+				r.cancel()
+				return Message{}, errGone
+			}
+			grace = time.After(left)
+		}
+
+		select {
+		case msg := <-r.replies:
+			if deadline != nil {
+				r.clock -= time.Since(started)
+			}
+			return msg, nil
+		case <-r.changed:
+		case <-grace:
+		case <-deadline:
+			r.clock = 0
+			r.cancel()
+			r.send(Message{Type: TypeError, Error: "out of time"})
+			return Message{}, errTimesUp
+		}
+	}
+}
+
+// decision is what a turn clock is wound for: the actions of one turn, or
+// a request outside the turns such as the mulligan. Requests asked again
+// for the same decision share its clock.
+type decision struct {
+	request string
+	turn    int
+}
+
+// cancel withdraws the pending request.
+func (r *remote) cancel() {
+	r.mu.Lock()
+	r.pending = ""
+	r.mu.Unlock()
+}
+
+func (r *remote) Mulligan(v game.View) []int {
+	for {
+		msg, err := r.ask(TypeMulligan)
+		if err != nil {
+			// gone or out of time: keep the hand
+			return nil
+		}
+		if err := v.CheckMulligan(msg.Indices); err != nil {
+			r.send(Message{Type: TypeError, Error: err.Error()})
+			continue
+		}
+		return msg.Indices
+	}
+}
+
+func (r *remote) NextAction(v game.View) game.Action {
+	for {
+		msg, err := r.ask(TypeAction)
+		switch err {
+		case errGone:
+			return game.Action{Kind: game.ActionConcede}
+		case errTimesUp:
+			return game.Action{Kind: game.ActionEndTurn}
+		}
+		if msg.Action == nil {
+			r.send(Message{Type: TypeError, Error: "action message without an action"})
+			continue
+		}
+		a, err := msg.Action.GameAction()
+		if err != nil {
+			r.send(Message{Type: TypeError, Error: err.Error()})
+			continue
+		}
+		return a
+	}
+}
+
+func (r *remote) ActionResult(a game.Action, err error) {
+	if err != nil {
+		r.send(Message{Type: TypeError, Error: err.Error()})
+	} else if a.Kind == game.ActionPlayCard {
+		r.played()
+	}
+}
@@ -0,0 +1,286 @@
+package netplay
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"GoGame/internal/card"
+	"GoGame/internal/fairplay"
+	"GoGame/internal/game"
+	"GoGame/internal/player"
+)
+
+// maxLine bounds the size of a single protocol message.
+const maxLine = 64 * 1024
+
+// DefaultGrace is how long NewServer holds the seat of a disconnected player.
+const DefaultGrace = 30 * time.Second
+
+// Server pairs up TCP clients and hosts a game for each pair.
+type Server struct {
+	Rules game.Rules
+	Decks [2][]card.Card // nil uses InitializeDeck
+	// Seed makes game n of the server use Seed+n; 0 seeds from the clock.
+	Seed int64
+	// Grace is how long the seat of a disconnected player is held for a
+	// resume before the player concedes; 0 concedes at once.
+	Grace time.Duration
+	// TurnTime limits the time of a player for the mulligan and for each
+	// turn; 0 is unlimited. A player out of time keeps their hand or ends
+	// the turn. The clock stops while the player is disconnected.
+	TurnTime time.Duration
+	// Verifiable makes the seats agree on the shuffle seed with a
+	// commit-reveal exchange before the game and sends them the proof after
+	// the result, so they can check every draw; see package fairplay. Seed
+	// is not used then.
+	Verifiable bool
+	// Log receives one line per game; nil discards it.
+	Log *log.Logger
+
+	mu       sync.Mutex
+	games    int64
+	waiting  *Seat              // said hello, waits for an opponent
+	sessions map[string]*remote // seats of running games by session token
+}
+
+// NewServer creates a server with the default rules and decks.
+func NewServer() *Server {
+	return &Server{Rules: game.DefaultRules(), Grace: DefaultGrace}
+}
+
+// Serve accepts connections until l is closed. Every two clients that say
+// hello play a game in its own goroutine; a client that resumes returns to
+// its seat.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handle(NewConn(conn))
+	}
+}
+
+func (s *Server) handle(c *Conn) {
+	msg, err := c.first()
+	if err != nil {
+		c.Close()
+		return
+	}
+	if msg.Type == TypeResume {
+		s.Resume(c, msg)
+		return
+	}
+
+	s.mu.Lock()
+	opponent := s.waiting
+	if opponent == nil {
+		s.waiting = &Seat{Name: msg.Name, Conn: c}
+	} else {
+		s.waiting = nil
+	}
+	s.mu.Unlock()
+	if opponent == nil {
+		return
+	}
+	if _, err := s.Host([2]Seat{*opponent, {Name: msg.Name, Conn: c}}, s.Rules); err != nil {
+		s.logf("game: %v", err)
+	}
+}
+
+// OpenSeats returns the number of seats open in the next game Serve starts.
+func (s *Server) OpenSeats() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.waiting != nil {
+		return 1
+	}
+	return 2
+}
+
+// Play runs one game between two connected clients and closes both
+// connections when it is over. Both clients must start with a hello.
+func (s *Server) Play(conns [2]net.Conn) (game.Result, error) {
+	var seats [2]Seat
+	for i, conn := range conns {
+		seats[i].Conn = NewConn(conn)
+	}
+	for i := range seats {
+		msg, err := seats[i].Conn.first()
+		if err == nil && msg.Type != TypeHello {
+			err = fmt.Errorf("%s instead of hello", msg.Type)
+		}
+		if err != nil {
+			seats[0].Conn.Close()
+			seats[1].Conn.Close()
+			return game.Result{}, fmt.Errorf("seat %d: %w", i+1, err)
+		}
+		seats[i].Name = msg.Name
+	}
+	return s.Host(seats, s.Rules)
+}
+
+// Seat is a player of a hosted game: a client on Conn, or a Controller
+// running in the server, such as an AI, when Conn is nil.
+type Seat struct {
+	Name       string
+	Conn       *Conn
+	Controller game.Controller
+}
+
+// Host runs one game with the given rules, starting with the welcome, and
+// closes the client connections when it is over.
+func (s *Server) Host(seats [2]Seat, rules game.Rules) (game.Result, error) {
+	g, err := s.newGame(rules)
+	if err != nil {
+		for _, seat := range seats {
+			if seat.Conn != nil {
+				seat.Conn.Close()
+			}
+		}
+		return game.Result{}, err
+	}
+	players := [2]*player.Player{&g.Player1, &g.Player2}
+	for i, seat := range seats {
+		if seat.Name != "" {
+			players[i].Name = seat.Name
+		}
+	}
+	if g.Player1.Name == g.Player2.Name {
+		g.Player2.Name += " (2)"
+	}
+
+	t := &table{g: g}
+	t.mu.Lock()
+	for i, seat := range seats {
+		if seat.Conn == nil {
+			g.SetController(players[i], seat.Controller)
+			continue
+		}
+		r := newRemote(s, t, i+1, players[i])
+		t.remotes = append(t.remotes, r)
+		g.SetController(players[i], r)
+	}
+	s.register(t)
+	defer s.unregister(t)
+	for _, r := range t.remotes {
+		c := seats[r.seat-1].Conn
+		c.Send(r.welcome())
+		r.attach(c)
+	}
+
+	for _, r := range t.remotes {
+		r.played = func() {
+			play := g.LastPlay
+			for _, r := range t.remotes {
+				r.send(Message{Type: TypePlay, Play: &play})
+			}
+		}
+	}
+	g.UIUpdate = func() {
+		for _, r := range t.remotes {
+			r.update()
+		}
+	}
+	var secrets [2]fairplay.Secret
+	if s.Verifiable {
+		if secrets, err = s.agree(t); err != nil {
+			for _, r := range t.remotes {
+				r.send(Message{Type: TypeError, Error: err.Error()})
+			}
+			t.mu.Unlock()
+			return game.Result{}, err
+		}
+	}
+	g.DealInitialHands()
+	g.UIUpdate()
+	g.GameLoop()
+
+	result := g.Result()
+	wire := ResultOf(result)
+	t.result = &wire
+	for _, r := range t.remotes {
+		r.send(Message{Type: TypeResult, Result: &wire})
+	}
+	if s.Verifiable {
+		t.prove(g, secrets)
+	}
+	t.mu.Unlock()
+	s.logf("%s vs %s: %s (%s) after %d turns",
+		g.Player1.Name, g.Player2.Name, wire.Winner, result.Reason, g.TurnCount)
+	return result, nil
+}
+
+// Resume returns a client to the seat of the session token in msg, a
+// resume message that c started with.
+func (s *Server) Resume(c *Conn, msg Message) {
+	s.mu.Lock()
+	r := s.sessions[msg.Token]
+	s.mu.Unlock()
+	if r == nil {
+		c.Send(Message{Type: TypeError, Error: "unknown session"})
+		c.Close()
+		return
+	}
+	r.table.mu.Lock()
+	defer r.table.mu.Unlock()
+	r.resume(c, msg.Since)
+	s.logf("%s resumed", r.player.Name)
+}
+
+func (s *Server) register(t *table) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = make(map[string]*remote)
+	}
+	for _, r := range t.remotes {
+		s.sessions[r.token] = r
+	}
+}
+
+// unregister forgets the tokens of a finished game and disconnects it.
+func (s *Server) unregister(t *table) {
+	s.mu.Lock()
+	for _, r := range t.remotes {
+		delete(s.sessions, r.token)
+	}
+	s.mu.Unlock()
+	for _, r := range t.remotes {
+		r.close()
+	}
+}
+
+func (s *Server) newGame(rules game.Rules) (*game.Game, error) {
+	decks := s.Decks
+	for i := range decks {
+		if decks[i] == nil {
+			decks[i] = game.InitializeDeck()
+		}
+	}
+	g, err := game.NewGameWithDecks(decks[0], decks[1], rules)
+	if err != nil {
+		return nil, err
+	}
+	g.Output = io.Discard
+
+	s.mu.Lock()
+	seed := s.Seed + s.games
+	if s.Seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	s.games++
+	s.mu.Unlock()
+	g.Seed(seed)
+	return g, nil
+}
+
+func (s *Server) logf(format string, args ...interface{}) {
+	if s.Log != nil {
+		s.Log.Printf(format, args...)
+	}
+}
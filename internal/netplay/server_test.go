@@ -0,0 +1,481 @@
+package netplay
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"GoGame/internal/fairplay"
+	"GoGame/internal/game"
+)
+
+// startServer serves games on a localhost port until the test ends.
+func startServer(t *testing.T, configure ...func(*Server)) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	s := NewServer()
+	s.Seed = 1
+	s.Rules.TurnLimit = 8
+	for _, f := range configure {
+		f(s)
+	}
+	go s.Serve(l)
+	return l.Addr().String()
+}
+
+// chooser picks the answer to an action request from the latest state.
+type chooser func(state *game.View, request int) Action
+
+// playCheapest plays the weakest card, then ends the turn.
+func playCheapest(state *game.View, request int) Action {
+	if request%2 == 1 {
+		return Action{Kind: "end"}
+	}
+	best := -1
+	for i, c := range state.Self.Hand {
+		if best < 0 || c.Power < state.Self.Hand[best].Power {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Action{Kind: "end"}
+	}
+	return Action{Kind: "play", Card: best}
+}
+
+// runClient plays a whole game and returns every message it received.
+func runClient(addr, name string, choose chooser) ([]Message, error) {
+	c, err := Dial(addr, name)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	return playOn(c, choose, nil)
+}
+
+// playOn answers requests on c until the result, or until leave returns
+// true for a message, and returns what it received.
+func playOn(c *Client, choose chooser, leave func(Message) bool) ([]Message, error) {
+	var received []Message
+	c.conn.SetDeadline(time.Now().Add(10 * time.Second))
+	var state *game.View
+	requests := 0
+	for {
+		msg, err := c.Receive()
+		if err != nil {
+			return received, err
+		}
+		received = append(received, msg)
+		if leave != nil && leave(msg) {
+			return received, nil
+		}
+		switch msg.Type {
+		case TypeState:
+			state = msg.State
+		case TypeRequest:
+			if msg.Request == TypeMulligan {
+				err = c.Mulligan(nil)
+			} else {
+				err = c.Act(choose(state, requests))
+				requests++
+			}
+			if err != nil {
+				return received, err
+			}
+		case TypeResult:
+			return received, nil
+		}
+	}
+}
+
+func playGame(t *testing.T, choose [2]chooser) [2][]Message {
+	t.Helper()
+	addr := startServer(t)
+	type outcome struct {
+		msgs []Message
+		err  error
+	}
+	done := [2]chan outcome{make(chan outcome, 1), make(chan outcome, 1)}
+	for i, name := range []string{"Alice", "Bob"} {
+		go func(i int, name string) {
+			msgs, err := runClient(addr, name, choose[i])
+			done[i] <- outcome{msgs, err}
+		}(i, name)
+		// connect in order so Alice gets seat 1
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	var msgs [2][]Message
+	for i := range done {
+		o := <-done[i]
+		if o.err != nil {
+			t.Fatalf("client %d: %v", i+1, o.err)
+		}
+		msgs[i] = o.msgs
+	}
+	return msgs
+}
+
+func last(msgs []Message, typ string) *Message {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Type == typ {
+			return &msgs[i]
+		}
+	}
+	return nil
+}
+
+func TestTwoClientsPlayAGame(t *testing.T) {
+	msgs := playGame(t, [2]chooser{playCheapest, playCheapest})
+
+	for i, name := range []string{"Alice", "Bob"} {
+		welcome := msgs[i][0]
+		if welcome.Type != TypeWelcome || welcome.Seat != i+1 || welcome.Name != name {
+			t.Fatalf("client %d: first message %+v", i+1, welcome)
+		}
+	}
+	r1, r2 := last(msgs[0], TypeResult), last(msgs[1], TypeResult)
+	if r1 == nil || r2 == nil || *r1.Result != *r2.Result {
+		t.Fatalf("results differ: %+v, %+v", r1, r2)
+	}
+	if r1.Result.Reason != game.EndTurnLimit.String() {
+		t.Fatalf("game ended by %q, want the turn limit", r1.Result.Reason)
+	}
+
+	plays := 0
+	for i, name := range []string{"Alice", "Bob"} {
+		for _, msg := range msgs[i] {
+			switch msg.Type {
+			case TypeState:
+				if msg.State.Self.Name != name {
+					t.Fatalf("%s got the state of %s", name, msg.State.Self.Name)
+				}
+				if len(msg.State.Opponent.Hand) != 0 {
+					t.Fatalf("%s saw the opponent's hand", name)
+				}
+			case TypeEvent:
+				e := msg.Event
+				if e.Kind == game.EventDraw && (e.Player == name) != (len(e.Cards) > 0) {
+					t.Fatalf("%s: draw event %+v shows the wrong cards", name, e)
+				}
+				if e.Kind == game.EventPlay {
+					plays++
+				}
+			}
+		}
+	}
+	if plays == 0 {
+		t.Fatal("no card was played")
+	}
+}
+
+func TestIllegalActionIsRejected(t *testing.T) {
+	cheat := func(state *game.View, request int) Action {
+		if request == 0 {
+			return Action{Kind: "play", Card: 99}
+		}
+		return playCheapest(state, request)
+	}
+	msgs := playGame(t, [2]chooser{cheat, playCheapest})
+
+	for i, msg := range msgs[0] {
+		if msg.Type == TypeError {
+			if msg.Error != game.ErrNoSuchCard.Error() {
+				t.Fatalf("error %q", msg.Error)
+			}
+			if next := msgs[0][i+1]; next.Type != TypeRequest || next.Request != TypeAction {
+				t.Fatalf("rejected action followed by %+v", next)
+			}
+			return
+		}
+	}
+	t.Fatal("illegal action was not rejected")
+}
+
+func TestBadMulliganIsAskedAgain(t *testing.T) {
+	addr := startServer(t)
+	alice, err := Dial(addr, "Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer alice.Close()
+	go runClient(addr, "Bob", playCheapest)
+
+	alice.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	var received []Message
+	for asked := 0; asked < 2; {
+		msg, err := alice.Receive()
+		if err != nil {
+			t.Fatal(err)
+		}
+		received = append(received, msg)
+		if msg.Type != TypeRequest || msg.Request != TypeMulligan {
+			continue
+		}
+		asked++
+		if asked == 1 {
+			err = alice.Mulligan([]int{0, 99})
+		} else {
+			err = alice.Mulligan([]int{0})
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bad := last(received, TypeError)
+	if bad == nil || !strings.Contains(bad.Error, "out of range") {
+		t.Fatalf("bad mulligan answered with %+v", bad)
+	}
+	msgs, err := playOn(alice, playCheapest, func(msg Message) bool {
+		return msg.Type == TypeEvent && msg.Event.Kind == game.EventMulligan && msg.Event.Player == "Alice"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := msgs[len(msgs)-1].Event; e.Amount != 1 {
+		t.Fatalf("mulligan event %+v, want Alice's second answer", e)
+	}
+}
+
+func TestMulliganHasItsOwnClock(t *testing.T) {
+	const think = 250 * time.Millisecond
+	addr := startServer(t, func(s *Server) { s.TurnTime = 400 * time.Millisecond })
+
+	// both players take their time over the mulligan and their first turn,
+	// which only fit in the turn time if each gets a clock of its own
+	slow := func(name string, errs chan<- error) {
+		c, err := Dial(addr, name)
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer c.Close()
+		c.conn.SetDeadline(time.Now().Add(10 * time.Second))
+		for {
+			msg, err := c.Receive()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if msg.Type == TypeRequest && msg.Request == TypeMulligan {
+				time.Sleep(think)
+				if err := c.Mulligan(nil); err != nil {
+					errs <- err
+					return
+				}
+				break
+			}
+		}
+		msgs, err := playOn(c, func(state *game.View, request int) Action {
+			if request == 0 {
+				time.Sleep(think)
+			}
+			return playCheapest(state, request)
+		}, nil)
+		if bad := last(msgs, TypeError); err == nil && bad != nil {
+			err = fmt.Errorf("%s: %s", name, bad.Error)
+		}
+		errs <- err
+	}
+
+	errs := make(chan error, 2)
+	go slow("Alice", errs)
+	time.Sleep(20 * time.Millisecond)
+	go slow("Bob", errs)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDisconnectConcedes(t *testing.T) {
+	addr := startServer(t, func(s *Server) { s.Grace = 200 * time.Millisecond })
+	result := make(chan *Message, 1)
+	go func() {
+		msgs, err := runClient(addr, "Alice", playCheapest)
+		if err != nil {
+			t.Error(err)
+		}
+		result <- last(msgs, TypeResult)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	bob, err := Dial(addr, "Bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := bob.Receive(); err != nil || msg.Type != TypeWelcome {
+		t.Fatalf("welcome: %+v %v", msg, err)
+	}
+	bob.Close()
+
+	select {
+	case r := <-result:
+		if r == nil || r.Result.Winner != "Alice" || r.Result.Reason != game.EndConcede.String() {
+			t.Fatalf("result %+v", r)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("game did not end")
+	}
+}
+
+func count(msgs []Message, typ string) int {
+	n := 0
+	for _, msg := range msgs {
+		if msg.Type == typ {
+			n++
+		}
+	}
+	return n
+}
+
+func TestResumeAfterDrop(t *testing.T) {
+	const away = 1500 * time.Millisecond
+	addr := startServer(t, func(s *Server) {
+		s.Grace = 5 * time.Second
+		s.TurnTime = time.Second
+	})
+	alice := make(chan []Message, 1)
+	go func() {
+		msgs, err := runClient(addr, "Alice", playCheapest)
+		if err != nil {
+			t.Error(err)
+		}
+		alice <- msgs
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	bob, err := Dial(addr, "Bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// drop the connection at the first action request, in the middle of
+	// Bob's turn
+	msgs, err := playOn(bob, playCheapest, func(msg Message) bool {
+		return msg.Type == TypeRequest && msg.Request == TypeAction
+	})
+	bob.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	welcome := msgs[0]
+	if welcome.Token == "" {
+		t.Fatal("welcome without a session token")
+	}
+	time.Sleep(away) // longer than the turn time
+
+	bob, err = Resume(addr, welcome.Token, count(msgs, TypeEvent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bob.Close()
+	resumed, err := playOn(bob, playCheapest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := resumed[0]; again.Type != TypeWelcome || again.Seat != welcome.Seat || again.Token != welcome.Token {
+		t.Fatalf("resumed with %+v", again)
+	}
+	first := -1
+	for i, msg := range resumed {
+		if msg.Type == TypeError {
+			t.Fatalf("error after resume: %s", msg.Error)
+		}
+		if first < 0 && msg.Type == TypeRequest {
+			first = i
+		}
+	}
+	if first < 0 || resumed[first-1].Type != TypeState || !resumed[first-1].State.MyTurn {
+		t.Fatal("the pending request was not sent again after the snapshot")
+	}
+
+	r := last(resumed, TypeResult)
+	if r == nil || r.Result.Reason != game.EndTurnLimit.String() {
+		t.Fatalf("result %+v", r)
+	}
+	msgs = append(msgs, resumed...)
+	if a, b := count(<-alice, TypeEvent), count(msgs, TypeEvent); a != b {
+		t.Fatalf("Alice got %d events, Bob %d", a, b)
+	}
+}
+
+func TestResumeUnknownToken(t *testing.T) {
+	addr := startServer(t)
+	c, err := Resume(addr, "nope", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if msg, err := c.Receive(); err != nil || msg.Type != TypeError {
+		t.Fatalf("got %+v %v", msg, err)
+	}
+}
+
+// exchange answers the commit and reveal requests of a verifiable game,
+// committing to one secret and revealing another, until it gets an error.
+func exchange(c *Client, commit, reveal fairplay.Secret) (Message, error) {
+	c.conn.SetDeadline(time.Now().Add(10 * time.Second))
+	hash := commit.Commit()
+	for {
+		msg, err := c.Receive()
+		if err != nil || msg.Type == TypeError {
+			return msg, err
+		}
+		switch {
+		case msg.Type == TypeRequest && msg.Request == TypeCommit:
+			err = c.Send(Message{Type: TypeCommit, Commit: &hash})
+		case msg.Type == TypeRequest && msg.Request == TypeReveal:
+			if msg.Commit == nil || msg.Digest == nil {
+				return msg, fmt.Errorf("reveal request without the commitment and digest")
+			}
+			err = c.Send(Message{Type: TypeReveal, Secret: &reveal})
+		}
+		if err != nil {
+			return msg, err
+		}
+	}
+}
+
+func TestRevealMustMatchCommitment(t *testing.T) {
+	addr := startServer(t, func(s *Server) { s.Verifiable = true })
+	alice := make(chan Message, 1)
+	go func() {
+		c, err := Dial(addr, "Alice")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer c.Close()
+		secret := fairplay.NewSecret()
+		msg, err := exchange(c, secret, secret)
+		if err != nil {
+			t.Error(err)
+		}
+		alice <- msg
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	bob, err := Dial(addr, "Bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bob.Close()
+	msg, err := exchange(bob, fairplay.NewSecret(), fairplay.NewSecret())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []Message{msg, <-alice} {
+		if !strings.Contains(msg.Error, "cheating") || !strings.Contains(msg.Error, "Bob") {
+			t.Fatalf("error %q, want Bob's reveal reported as cheating", msg.Error)
+		}
+	}
+}
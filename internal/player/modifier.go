@@ -0,0 +1,152 @@
+package player
+
+import (
+	"sort"
+)
+
+// Stat — характеристика игрока, на которую могут действовать модификаторы
+type Stat int
+
+const (
+	StatMaxHealth Stat = iota
+	StatMaxMana
+	StatArmor
+	StatDamage
+	StatHealing
+)
+
+// ModifierKind определяет, как значение модификатора применяется к характеристике
+type ModifierKind int
+
+const (
+	FlatModifier    ModifierKind = iota // прибавляет Value
+	PercentModifier                     // прибавляет Value процентов от текущего значения
+)
+
+// Modifier — одно изменение характеристики от экипировки, ауры или статуса
+type Modifier struct {
+	Source   string       `json:"source"`
+	Stat     Stat         `json:"stat"`
+	Kind     ModifierKind `json:"kind"`
+	Value    int          `json:"value"`
+	Duration int          `json:"duration,omitempty"` // в ходах владельца, 0 — бессрочно
+	Priority int          `json:"priority,omitempty"` // меньший приоритет применяется раньше
+
+	slot string // слот экипировки, выдавшей модификатор
+}
+
+// ModifierStep описывает вклад одного модификатора в итоговое значение
+type ModifierStep struct {
+	Source string
+	Amount int
+}
+
+// AddModifier регистрирует модификатор у игрока
+func (p *Player) AddModifier(m Modifier) {
+	p.Modifiers = append(p.Modifiers, m)
+	p.clampResources()
+}
+
+// RemoveModifiers снимает все модификаторы указанного источника и возвращает их
+func (p *Player) RemoveModifiers(source string) []Modifier {
+	var removed []Modifier
+	// новый срез: копии игрока могут разделять старый
+	kept := make([]Modifier, 0, len(p.Modifiers))
+	for _, m := range p.Modifiers {
+		if m.Source == source {
+			removed = append(removed, m)
+		} else {
+			kept = append(kept, m)
+		}
+	}
+	p.Modifiers = kept
+	p.clampResources()
+	return removed
+}
+
+// removeSlotModifiers снимает ровно те модификаторы, что выдал предмет из слота
+func (p *Player) removeSlotModifiers(slot string) {
+	kept := make([]Modifier, 0, len(p.Modifiers))
+	for _, m := range p.Modifiers {
+		if m.slot != slot {
+			kept = append(kept, m)
+		}
+	}
+	p.Modifiers = kept
+	p.clampResources()
+}
+
+// TickModifiers уменьшает длительность временных модификаторов и снимает истёкшие
+func (p *Player) TickModifiers() {
+	kept := make([]Modifier, 0, len(p.Modifiers))
+	for _, m := range p.Modifiers {
+		if m.Duration > 0 {
+			m.Duration--
+			if m.Duration == 0 {
+				continue
+			}
+		}
+		kept = append(kept, m)
+	}
+	p.Modifiers = kept
+	p.clampResources()
+}
+
+// ApplyModifiers вычисляет значение характеристики из базы и модификаторов
+// и возвращает вклад каждого модификатора в порядке применения
+func (p *Player) ApplyModifiers(stat Stat, base int) (int, []ModifierStep) {
+	var active []Modifier
+	for _, m := range p.Modifiers {
+		if m.Stat == stat {
+			active = append(active, m)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].Priority < active[j].Priority
+	})
+
+	value := base
+	var steps []ModifierStep
+	for _, m := range active {
+		delta := m.Value
+		if m.Kind == PercentModifier {
+			delta = value * m.Value / 100
+		}
+		value += delta
+		steps = append(steps, ModifierStep{Source: m.Source, Amount: delta})
+	}
+	return value, steps
+}
+
+func (p *Player) effective(stat Stat, base int) int {
+	value, _ := p.ApplyModifiers(stat, base)
+	if value < 0 {
+		return 0
+	}
+	return value
+}
+
+// EffectiveMaxHealth возвращает максимум здоровья с учётом модификаторов
+func (p *Player) EffectiveMaxHealth() int {
+	return p.effective(StatMaxHealth, p.MaxHealth)
+}
+
+// EffectiveMaxMana возвращает максимум маны с учётом модификаторов
+func (p *Player) EffectiveMaxMana() int {
+	return p.effective(StatMaxMana, p.MaxMana)
+}
+
+// EffectiveArmor возвращает броню с учётом модификаторов
+func (p *Player) EffectiveArmor() int {
+	return p.effective(StatArmor, p.Armor)
+}
+
+// clampResources не даёт текущим здоровью и мане превышать эффективный максимум
+func (p *Player) clampResources() {
+	if maxHealth := p.EffectiveMaxHealth(); p.Health > maxHealth {
+		p.Health = maxHealth
+	}
+	if maxMana := p.EffectiveMaxMana(); p.Mana > maxMana {
+		p.Mana = maxMana
+	}
+}
@@ -0,0 +1,100 @@
+package player
+
+import "testing"
+
+func TestModifiersExpire(t *testing.T) {
+	p := NewPlayer("Alice")
+	p.AddModifier(Modifier{Source: "Shield Wall", Stat: StatArmor, Value: 3, Duration: 2})
+	p.AddModifier(Modifier{Source: "Aura", Stat: StatArmor, Value: 1})
+
+	p.TickModifiers()
+	if got := p.EffectiveArmor(); got != 4 {
+		t.Fatalf("armor after one turn %d, want 4", got)
+	}
+	p.TickModifiers()
+	if got := p.EffectiveArmor(); got != 1 || len(p.Modifiers) != 1 {
+		t.Fatalf("armor after two turns %d with %d modifiers, want only the aura", got, len(p.Modifiers))
+	}
+}
+
+func TestFilteringDoesNotTouchCopies(t *testing.T) {
+	p := NewPlayer("Alice")
+	p.AddModifier(Modifier{Source: "A", Stat: StatArmor, Value: 1, Duration: 1})
+	p.AddModifier(Modifier{Source: "B", Stat: StatArmor, Value: 2})
+	p.AddModifier(Modifier{Source: "C", Stat: StatArmor, Value: 3})
+	shared := *p
+
+	p.TickModifiers()
+	p.RemoveModifiers("C")
+	if len(shared.Modifiers) != 3 || shared.Modifiers[0].Source != "A" || shared.Modifiers[2].Source != "C" {
+		t.Fatalf("copy changed to %+v", shared.Modifiers)
+	}
+}
+
+func TestUnequipUndoesExactlyTheItem(t *testing.T) {
+	p := NewPlayer("Alice")
+	p.AddModifier(Modifier{Source: "Blessing", Stat: StatMaxMana, Value: 1})
+	p.EquipItem(&Item{Name: "Ring", Bonus: 4}, "ring")
+	if got := p.EffectiveMaxMana(); got != 15 {
+		t.Fatalf("max mana %d, want 15", got)
+	}
+	p.Mana = 15
+
+	if item := p.UnequipItem("ring"); item == nil || item.Name != "Ring" {
+		t.Fatalf("unequipped %+v", item)
+	}
+	if got := p.EffectiveMaxMana(); got != 11 || p.Mana != 11 {
+		t.Fatalf("max mana %d, mana %d after unequip, want 11", got, p.Mana)
+	}
+}
+
+func TestPercentModifiersScaleTheAmount(t *testing.T) {
+	p := NewPlayer("Alice")
+	p.AddModifier(Modifier{Source: "Fury", Stat: StatDamage, Kind: PercentModifier, Value: 50, Priority: 1})
+	p.AddModifier(Modifier{Source: "Sword", Stat: StatDamage, Value: 2})
+
+	// the flat sword applies first, then fury adds half of the result
+	if got := p.DamageBonus(10); got != 8 {
+		t.Fatalf("bonus to 10 damage %d, want 8", got)
+	}
+	total, steps := p.ApplyModifiers(StatDamage, 10)
+	if total != 18 || len(steps) != 2 || steps[0].Source != "Sword" || steps[1].Amount != 6 {
+		t.Fatalf("total %d, steps %+v", total, steps)
+	}
+	// with no base damage fury only scales the sword
+	if got := p.DamageBonus(0); got != 3 {
+		t.Fatalf("bonus to no damage %d, want 3", got)
+	}
+}
+
+func TestBaseArmorIsUsedUp(t *testing.T) {
+	p := NewPlayer("Alice")
+	p.AddArmor(4)
+
+	p.TakeDamage(3)
+	if p.Health != 100 || p.Armor != 1 {
+		t.Fatalf("health %d armor %d after 3 damage, want 100 and 1", p.Health, p.Armor)
+	}
+	p.TakeDamage(3)
+	if p.Health != 98 || p.Armor != 0 {
+		t.Fatalf("health %d armor %d after 3 more, want 98 and 0", p.Health, p.Armor)
+	}
+}
+
+func TestModifierArmorReducesEveryHit(t *testing.T) {
+	p := NewPlayer("Alice")
+	p.AddModifier(Modifier{Source: "Aura", Stat: StatArmor, Value: 2})
+	p.AddArmor(1)
+
+	p.TakeDamage(4)
+	if p.Health != 99 || p.Armor != 0 {
+		t.Fatalf("health %d armor %d after 4 damage, want 99 and 0", p.Health, p.Armor)
+	}
+	p.TakeDamage(4)
+	if p.Health != 97 {
+		t.Fatalf("health %d after 4 more, want 97", p.Health)
+	}
+	if got := p.EffectiveArmor(); got != 2 {
+		t.Fatalf("armor %d after two hits, want the aura's 2", got)
+	}
+}
@@ -6,9 +6,9 @@ import (
 
 // Item представляет предмет экипировки
 type Item struct {
-	Name        string
-	Description string
-	Bonus       int
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Bonus       int    `json:"bonus"`
 }
 
 // Player представляет игрока
@@ -24,6 +24,7 @@ type Player struct {
 	Ring      *Item
 	Necklace  *Item
 	Weapon    *Item
+	Modifiers []Modifier
 }
 
 // NewPlayer создает нового игрока
@@ -37,8 +38,64 @@ func NewPlayer(name string) *Player {
 	}
 }
 
-// EquipItem экипирует предмет в соответствующий слот
+// Clone возвращает независимую копию игрока: рука, модификаторы и предметы
+// копируются, а не разделяются с оригиналом
+func (p *Player) Clone() *Player {
+	c := *p
+	c.Hand = append([]card.Card(nil), p.Hand...)
+	c.Modifiers = append([]Modifier(nil), p.Modifiers...)
+	c.Ring = p.Ring.clone()
+	c.Necklace = p.Necklace.clone()
+	c.Weapon = p.Weapon.clone()
+	return &c
+}
+
+func (it *Item) clone() *Item {
+	if it == nil {
+		return nil
+	}
+	c := *it
+	return &c
+}
+
+// Equal сравнивает состояние двух игроков. Эффекты карт не сравниваются,
+// карты считаются равными по своим данным
+func (p *Player) Equal(o *Player) bool {
+	if p.Name != o.Name || p.Score != o.Score || p.Health != o.Health ||
+		p.MaxHealth != o.MaxHealth || p.Mana != o.Mana || p.MaxMana != o.MaxMana ||
+		p.Armor != o.Armor {
+		return false
+	}
+	if !itemEqual(p.Ring, o.Ring) || !itemEqual(p.Necklace, o.Necklace) || !itemEqual(p.Weapon, o.Weapon) {
+		return false
+	}
+	if len(p.Modifiers) != len(o.Modifiers) {
+		return false
+	}
+	for i := range p.Modifiers {
+		if p.Modifiers[i] != o.Modifiers[i] {
+			return false
+		}
+	}
+	return card.Equal(p.Hand, o.Hand)
+}
+
+func itemEqual(a, b *Item) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// EquipItem экипирует предмет в соответствующий слот.
+// Бонус предмета регистрируется как модификатор: оружие усиливает урон,
+// ожерелье — лечение, кольцо — максимум маны.
 func (p *Player) EquipItem(item *Item, slot string) bool {
+	stat, ok := slotStat(slot)
+	if !ok {
+		return false
+	}
+	p.UnequipItem(slot)
 	switch slot {
 	case "ring":
 		p.Ring = item
@@ -46,13 +103,20 @@ func (p *Player) EquipItem(item *Item, slot string) bool {
 		p.Necklace = item
 	case "weapon":
 		p.Weapon = item
-	default:
-		return false
+	}
+	if item != nil {
+		p.AddModifier(Modifier{
+			Source: item.Name,
+			Stat:   stat,
+			Kind:   FlatModifier,
+			Value:  item.Bonus,
+			slot:   slot,
+		})
 	}
 	return true
 }
 
-// UnequipItem снимает предмет из указанного слота
+// UnequipItem снимает предмет из указанного слота вместе с его модификаторами
 func (p *Player) UnequipItem(slot string) *Item {
 	var item *Item
 	switch slot {
@@ -66,9 +130,24 @@ func (p *Player) UnequipItem(slot string) *Item {
 		item = p.Weapon
 		p.Weapon = nil
 	}
+	if item != nil {
+		p.removeSlotModifiers(slot)
+	}
 	return item
 }
 
+func slotStat(slot string) (Stat, bool) {
+	switch slot {
+	case "ring":
+		return StatMaxMana, true
+	case "necklace":
+		return StatHealing, true
+	case "weapon":
+		return StatDamage, true
+	}
+	return 0, false
+}
+
 // AddArmor добавляет броню игроку
 func (p *Player) AddArmor(amount int) {
 	p.Armor += amount
@@ -77,25 +156,27 @@ func (p *Player) AddArmor(amount int) {
 	}
 }
 
-// TakeDamage наносит урон игроку
+// TakeDamage наносит урон игроку.
+// Броня от модификаторов не расходуется: пока модификатор действует, она
+// снижает каждый удар на своё значение. Остаток урона поглощает базовая
+// броня, и она при этом расходуется.
 func (p *Player) TakeDamage(amount int) {
-	if amount <= p.Armor {
-		p.Armor -= amount
-	} else {
-		remainingDamage := amount - p.Armor
-		p.Armor = 0
-		p.Health -= remainingDamage
-		if p.Health < 0 {
-			p.Health = 0
-		}
+	if reduction := p.EffectiveArmor() - p.Armor; reduction > 0 {
+		amount -= min(amount, reduction)
+	}
+	absorbed := min(amount, p.Armor)
+	p.Armor -= absorbed
+	p.Health -= amount - absorbed
+	if p.Health < 0 {
+		p.Health = 0
 	}
 }
 
 // Heal восстанавливает здоровье игрока
 func (p *Player) Heal(amount int) {
 	p.Health += amount
-	if p.Health > p.MaxHealth {
-		p.Health = p.MaxHealth
+	if maxHealth := p.EffectiveMaxHealth(); p.Health > maxHealth {
+		p.Health = maxHealth
 	}
 }
 
@@ -111,22 +192,35 @@ func (p *Player) UseMana(amount int) bool {
 // RestoreMana восстанавливает ману игрока
 func (p *Player) RestoreMana(amount int) {
 	p.Mana += amount
-	if p.Mana > p.MaxMana {
-		p.Mana = p.MaxMana
+	if maxMana := p.EffectiveMaxMana(); p.Mana > maxMana {
+		p.Mana = maxMana
 	}
 }
 
+// DamageBonus возвращает прибавку модификаторов к удару силой base;
+// процентные модификаторы считаются от base
+func (p *Player) DamageBonus(base int) int {
+	return p.effective(StatDamage, base) - base
+}
+
+// HealingBonus возвращает прибавку модификаторов к лечению силой base
+func (p *Player) HealingBonus(base int) int {
+	return p.effective(StatHealing, base) - base
+}
+
+// ManaBonus возвращает прибавку к максимуму маны от модификаторов
+func (p *Player) ManaBonus() int {
+	return p.EffectiveMaxMana() - p.MaxMana
+}
+
 // GetTotalBonus возвращает сумму бонусов от всех экипированных предметов
 func (p *Player) GetTotalBonus() int {
-	bonus := 0
-	if p.Ring != nil {
-		bonus += p.Ring.Bonus
-	}
-	if p.Necklace != nil {
-		bonus += p.Necklace.Bonus
-	}
-	if p.Weapon != nil {
-		bonus += p.Weapon.Bonus
+	return itemBonus(p.Ring) + itemBonus(p.Necklace) + itemBonus(p.Weapon)
+}
+
+func itemBonus(item *Item) int {
+	if item == nil {
+		return 0
 	}
-	return bonus
-}
\ No newline at end of file
+	return item.Bonus
+}
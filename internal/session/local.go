@@ -0,0 +1,84 @@
+package session
+
+import (
+	"errors"
+
+	"GoGame/internal/game"
+	"GoGame/internal/player"
+)
+
+// Local plays a seat of a game running in this process through the seat's
+// HumanController.
+type Local struct {
+	g *game.Game
+	p *player.Player
+}
+
+// NewLocal creates a session for Player1 of g.
+func NewLocal(g *game.Game) *Local {
+	return &Local{g: g, p: &g.Player1}
+}
+
+func (l *Local) human() *game.HumanController {
+	human, _ := l.g.Controller(l.p).(*game.HumanController)
+	return human
+}
+
+func (l *Local) View() game.View {
+	return l.g.ViewFor(l.p)
+}
+
+func (l *Local) Waiting() Decision {
+	if l.human() == nil || l.g.GameOver {
+		return DecisionNone
+	}
+	switch {
+	case l.g.CurrentPhase == game.MulliganPhase && !l.g.MulliganDone(l.p):
+		return DecisionMulligan
+	case l.g.CurrentPlayer == l.p && l.g.CurrentPhase == game.PlayPhase:
+		return DecisionAction
+	}
+	return DecisionNone
+}
+
+func (l *Local) Act(a game.Action) (game.PlayResult, error) {
+	if l.Waiting() != DecisionAction {
+		return game.PlayResult{}, game.ErrNotYourTurn
+	}
+	if err := l.human().Submit(a); err != nil {
+		return game.PlayResult{}, err
+	}
+	return l.g.LastPlay, nil
+}
+
+func (l *Local) Mulligan(indices []int) error {
+	if l.Waiting() != DecisionMulligan {
+		return errors.New("no mulligan is pending")
+	}
+	return l.human().SubmitMulligan(indices)
+}
+
+func (l *Local) Result() (Result, bool) {
+	if !l.g.CheckGameOver() {
+		return Result{}, false
+	}
+	r := l.g.Result()
+	out := Result{Reason: r.Reason.String()}
+	if r.Winner != nil {
+		out.Winner = r.Winner.Name
+	}
+	return out, true
+}
+
+func (l *Local) Status() Status {
+	return Status{State: StateLocal, Text: "Local game"}
+}
+
+func (l *Local) OnUpdate(f func()) {
+	l.g.UIUpdate = f
+}
+
+func (l *Local) Close() error {
+	l.g.UIUpdate = nil
+	return nil
+}
@@ -0,0 +1,339 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"GoGame/internal/fairplay"
+	"GoGame/internal/game"
+	"GoGame/internal/netplay"
+)
+
+// ErrDisconnected is returned for decisions that could not reach the server.
+var ErrDisconnected = errors.New("disconnected from the server")
+
+// How long and how often a Remote tries to resume a dropped connection.
+var (
+	resumeFor   = netplay.DefaultGrace
+	resumeEvery = 500 * time.Millisecond
+)
+
+// Remote plays a seat of a game hosted by a netplay server. When the
+// connection drops it resumes the seat with the session token.
+type Remote struct {
+	addr string
+
+	mu       sync.Mutex
+	client   *netplay.Client
+	token    string
+	events   []game.Event // events received, for resuming and the fairness check
+	resumeBy time.Time    // while reconnecting, when to give up
+	closed   bool
+	view     game.View
+	waiting  Decision
+	result   *netplay.Result
+	status   Status
+	onUpdate func()
+	reply    chan reply // set while Act waits for a card play to resolve
+
+	// commit-reveal exchange of a verifiable game, see package fairplay
+	seat     int
+	check    *fairplay.Check // set once the seat commits
+	verified bool            // the proof arrived and fair tells the verdict
+	fair     error
+}
+
+type reply struct {
+	play game.PlayResult
+	err  error
+}
+
+// Dial connects to the server at addr and joins its next game as name.
+func Dial(addr, name string) (*Remote, error) {
+	client, err := netplay.Dial(addr, name)
+	if err != nil {
+		return nil, err
+	}
+	r := &Remote{
+		client: client,
+		addr:   addr,
+		status: Status{State: StateConnected, Text: fmt.Sprintf("Connected to %s, waiting for an opponent", addr)},
+	}
+	go r.receive(client)
+	return r, nil
+}
+
+func (r *Remote) receive(client *netplay.Client) {
+	for {
+		msg, err := client.Receive()
+		if err != nil {
+			r.lost(client, err)
+			return
+		}
+		r.handle(msg)
+	}
+}
+
+// lost starts resuming after the connection of client dropped, unless the
+// game is over or the session closed.
+func (r *Remote) lost(client *netplay.Client, err error) {
+	r.mu.Lock()
+	if r.client != client {
+		r.mu.Unlock()
+		return
+	}
+	if r.resumeBy.IsZero() {
+		r.resumeBy = time.Now().Add(resumeFor)
+	}
+	if r.closed || r.result != nil || r.token == "" || time.Now().After(r.resumeBy) {
+		r.mu.Unlock()
+		r.disconnected(err)
+		return
+	}
+	r.waiting = DecisionNone
+	r.status = Status{State: StateReconnecting, Text: fmt.Sprintf("Connection to %s lost, reconnecting", r.addr)}
+	r.deliver(reply{err: ErrDisconnected})
+	f := r.onUpdate
+	r.mu.Unlock()
+
+	if f != nil {
+		f()
+	}
+	go r.resume()
+}
+
+func (r *Remote) resume() {
+	for {
+		time.Sleep(resumeEvery)
+		r.mu.Lock()
+		token, since, closed, deadline := r.token, len(r.events), r.closed, r.resumeBy
+		r.mu.Unlock()
+		if closed {
+			return
+		}
+
+		client, err := netplay.Resume(r.addr, token, since)
+		if err == nil {
+			r.mu.Lock()
+			if r.closed {
+				r.mu.Unlock()
+				client.Close()
+				return
+			}
+			r.client = client
+			r.mu.Unlock()
+			go r.receive(client)
+			return
+		}
+		if time.Now().After(deadline) {
+			r.disconnected(err)
+			return
+		}
+	}
+}
+
+func (r *Remote) handle(msg netplay.Message) {
+	r.mu.Lock()
+	update := true
+	var answer *netplay.Message
+	switch msg.Type {
+	case netplay.TypeWelcome:
+		r.token = msg.Token
+		r.seat = msg.Seat
+		r.resumeBy = time.Time{}
+		r.status = Status{State: StateConnected, Text: fmt.Sprintf("Connected to %s as %s (seat %d)", r.addr, msg.Name, msg.Seat)}
+	case netplay.TypeEvent:
+		if msg.Event != nil {
+			r.events = append(r.events, *msg.Event)
+		}
+		update = false
+	case netplay.TypeState:
+		if msg.State != nil {
+			r.view = *msg.State
+		}
+	case netplay.TypeRequest:
+		switch msg.Request {
+		case netplay.TypeMulligan:
+			r.waiting = DecisionMulligan
+		case netplay.TypeAction:
+			r.waiting = DecisionAction
+		case netplay.TypeCommit, netplay.TypeReveal:
+			answer = r.exchange(msg)
+			update = false
+		}
+	case netplay.TypePlay:
+		if msg.Play != nil {
+			r.deliver(reply{play: *msg.Play})
+		}
+		update = false
+	case netplay.TypeError:
+		r.deliver(reply{err: errors.New(msg.Error)})
+		update = false
+	case netplay.TypeResult:
+		r.result = msg.Result
+		r.waiting = DecisionNone
+	case netplay.TypeProof:
+		if r.check != nil && msg.Proof != nil {
+			r.check.Events = r.events
+			r.verified, r.fair = true, r.check.Verify(*msg.Proof)
+			r.status.Text = "Game over, " + r.verdict()
+		}
+	default:
+		update = false
+	}
+	f := r.onUpdate
+	client := r.client
+	r.mu.Unlock()
+
+	if answer != nil {
+		client.Send(*answer)
+	}
+	if update && f != nil {
+		f()
+	}
+}
+
+// exchange answers a commit or reveal request. The secret is made once, so
+// a request sent again after a resume gets the same answer. r.mu must be
+// held.
+func (r *Remote) exchange(msg netplay.Message) *netplay.Message {
+	if r.check == nil {
+		r.check = &fairplay.Check{Seat: r.seat, Secret: fairplay.NewSecret()}
+	}
+	if msg.Request == netplay.TypeCommit {
+		commit := r.check.Secret.Commit()
+		return &netplay.Message{Type: netplay.TypeCommit, Commit: &commit}
+	}
+	if msg.Commit == nil || msg.Digest == nil {
+		return nil
+	}
+	r.check.Seat, r.check.Opponent, r.check.Decks = r.seat, *msg.Commit, *msg.Digest
+	secret := r.check.Secret
+	return &netplay.Message{Type: netplay.TypeReveal, Secret: &secret}
+}
+
+// verdict describes the outcome of the fairness check. r.mu must be held.
+func (r *Remote) verdict() string {
+	if r.fair != nil {
+		return r.fair.Error()
+	}
+	return "every shuffle verified"
+}
+
+// Fairness reports whether the game was verifiable and, once its proof has
+// arrived, whether the proof held up. A failed check is a
+// *fairplay.CheatError.
+func (r *Remote) Fairness() (checked bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.verified, r.fair
+}
+
+// deliver hands the answer to a waiting Act. r.mu must be held.
+func (r *Remote) deliver(rep reply) {
+	if r.reply != nil {
+		r.reply <- rep
+		r.reply = nil
+	}
+}
+
+func (r *Remote) disconnected(err error) {
+	r.mu.Lock()
+	r.waiting = DecisionNone
+	r.status.State = StateDisconnected
+	if r.result != nil && r.verified {
+		r.status.Text = fmt.Sprintf("Game over, %s, disconnected from %s", r.verdict(), r.addr)
+	} else if r.result != nil {
+		r.status.Text = fmt.Sprintf("Game over, disconnected from %s", r.addr)
+	} else {
+		r.status.Text = fmt.Sprintf("Disconnected from %s: %v", r.addr, err)
+	}
+	r.deliver(reply{err: ErrDisconnected})
+	f := r.onUpdate
+	r.mu.Unlock()
+
+	if f != nil {
+		f()
+	}
+}
+
+func (r *Remote) View() game.View {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.view
+}
+
+func (r *Remote) Waiting() Decision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.waiting
+}
+
+func (r *Remote) Act(a game.Action) (game.PlayResult, error) {
+	r.mu.Lock()
+	if r.waiting != DecisionAction {
+		r.mu.Unlock()
+		return game.PlayResult{}, game.ErrNotYourTurn
+	}
+	r.waiting = DecisionNone
+	var answer chan reply
+	if a.Kind == game.ActionPlayCard {
+		answer = make(chan reply, 1)
+		r.reply = answer
+	}
+	client := r.client
+	r.mu.Unlock()
+
+	if err := client.Act(netplay.WireAction(a)); err != nil {
+		return game.PlayResult{}, err
+	}
+	if answer == nil {
+		return game.PlayResult{}, nil
+	}
+	rep := <-answer
+	return rep.play, rep.err
+}
+
+func (r *Remote) Mulligan(indices []int) error {
+	r.mu.Lock()
+	if r.waiting != DecisionMulligan {
+		r.mu.Unlock()
+		return errors.New("no mulligan is pending")
+	}
+	r.waiting = DecisionNone
+	client := r.client
+	r.mu.Unlock()
+	return client.Mulligan(indices)
+}
+
+func (r *Remote) Result() (Result, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.result == nil {
+		return Result{}, false
+	}
+	return Result{Winner: r.result.Winner, Reason: r.result.Reason}, true
+}
+
+func (r *Remote) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+func (r *Remote) OnUpdate(f func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onUpdate = f
+}
+
+func (r *Remote) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	r.onUpdate = nil
+	client := r.client
+	r.mu.Unlock()
+	return client.Close()
+}
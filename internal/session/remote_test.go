@@ -0,0 +1,225 @@
+package session
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"GoGame/internal/fairplay"
+	"GoGame/internal/game"
+	"GoGame/internal/netplay"
+)
+
+// passiveOpponent joins the game and ends every turn.
+func passiveOpponent(t *testing.T, addr string) {
+	c, err := netplay.Dial(addr, "Bob")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer c.Close()
+	secret := fairplay.NewSecret()
+	commit := secret.Commit()
+	for {
+		msg, err := c.Receive()
+		if err != nil || msg.Type == netplay.TypeResult {
+			return
+		}
+		if msg.Type == netplay.TypeRequest {
+			switch msg.Request {
+			case netplay.TypeMulligan:
+				c.Mulligan(nil)
+			case netplay.TypeCommit:
+				c.Send(netplay.Message{Type: netplay.TypeCommit, Commit: &commit})
+			case netplay.TypeReveal:
+				c.Send(netplay.Message{Type: netplay.TypeReveal, Secret: &secret})
+			default:
+				c.Act(netplay.Action{Kind: "end"})
+			}
+		}
+	}
+}
+
+// startServer serves games on a localhost port until the test ends.
+func startServer(t *testing.T, configure ...func(*netplay.Server)) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	server := netplay.NewServer()
+	server.Seed = 3
+	server.Rules.TurnLimit = 6
+	for _, f := range configure {
+		f(server)
+	}
+	go server.Serve(l)
+	return l.Addr().String()
+}
+
+// dial joins a game against a passive opponent and returns a channel that
+// receives the session's updates.
+func dial(t *testing.T, addr string) (*Remote, <-chan struct{}) {
+	t.Helper()
+	r, err := Dial(addr, "Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	updates := make(chan struct{}, 1)
+	r.OnUpdate(func() {
+		select {
+		case updates <- struct{}{}:
+		default:
+		}
+	})
+	if r.Status().State != StateConnected {
+		t.Fatalf("status %+v", r.Status())
+	}
+	go passiveOpponent(t, addr)
+	return r, updates
+}
+
+func TestRemotePlaysAGame(t *testing.T) {
+	r, updates := dial(t, startServer(t))
+
+	plays, rejected, playedOn := 0, false, -1
+	timeout := time.After(10 * time.Second)
+	for {
+		if _, over := r.Result(); over {
+			break
+		}
+		switch r.Waiting() {
+		case DecisionMulligan:
+			if err := r.Mulligan(nil); err != nil {
+				t.Fatal(err)
+			}
+		case DecisionAction:
+			if !rejected {
+				rejected = true
+				if _, err := r.Act(game.Action{Kind: game.ActionPlayCard, CardIndex: 99}); err == nil {
+					t.Fatal("illegal play was accepted")
+				}
+				continue
+			}
+			// one card a turn, then end it
+			action := game.Action{Kind: game.ActionEndTurn}
+			v := r.View()
+			if v.Turn != playedOn && len(v.Self.Hand) > 0 {
+				action = game.Action{Kind: game.ActionPlayCard}
+				playedOn = v.Turn
+			}
+			play, err := r.Act(action)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if action.Kind == game.ActionPlayCard {
+				if play.PlayerCard.Name != v.Self.Hand[action.CardIndex].Name {
+					t.Fatalf("played %s, got result for %s", v.Self.Hand[action.CardIndex].Name, play.PlayerCard.Name)
+				}
+				plays++
+			}
+			continue
+		}
+		select {
+		case <-updates:
+		case <-timeout:
+			t.Fatal("game did not finish")
+		}
+	}
+
+	if plays == 0 {
+		t.Fatal("no card was played")
+	}
+	if result, _ := r.Result(); result.Reason == "" {
+		t.Fatalf("result %+v has no reason", result)
+	}
+	if v := r.View(); v.Self.Name != "Alice" || len(v.Opponent.Hand) != 0 {
+		t.Fatalf("view self %q, opponent hand %d", v.Self.Name, len(v.Opponent.Hand))
+	}
+}
+
+func TestRemoteResumesDroppedConnection(t *testing.T) {
+	r, updates := dial(t, startServer(t))
+
+	dropped := false
+	timeout := time.After(10 * time.Second)
+	for {
+		if _, over := r.Result(); over {
+			break
+		}
+		switch r.Waiting() {
+		case DecisionMulligan:
+			r.Mulligan(nil)
+		case DecisionAction:
+			if !dropped {
+				dropped = true
+				r.mu.Lock()
+				r.client.Close()
+				r.mu.Unlock()
+				for r.Status().State != StateReconnecting {
+					select {
+					case <-updates:
+					case <-timeout:
+						t.Fatal("the drop was not noticed")
+					}
+				}
+				continue
+			}
+			if _, err := r.Act(game.Action{Kind: game.ActionEndTurn}); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		select {
+		case <-updates:
+		case <-timeout:
+			t.Fatal("game did not finish")
+		}
+	}
+
+	if !dropped {
+		t.Fatal("no action was requested")
+	}
+	if result, _ := r.Result(); result.Reason != game.EndTurnLimit.String() {
+		t.Fatalf("result %+v, want the turn limit after resuming", result)
+	}
+	if s := r.Status(); s.State != StateDisconnected {
+		t.Fatalf("status after the game %+v", s)
+	}
+}
+
+func TestRemoteVerifiesTheShuffle(t *testing.T) {
+	r, updates := dial(t, startServer(t, func(s *netplay.Server) { s.Verifiable = true }))
+
+	timeout := time.After(10 * time.Second)
+	for {
+		if checked, _ := r.Fairness(); checked {
+			break
+		}
+		switch r.Waiting() {
+		case DecisionMulligan:
+			r.Mulligan(nil)
+			continue
+		case DecisionAction:
+			if _, err := r.Act(game.Action{Kind: game.ActionEndTurn}); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		select {
+		case <-updates:
+		case <-timeout:
+			t.Fatal("no proof arrived")
+		}
+	}
+
+	if _, err := r.Fairness(); err != nil {
+		t.Fatalf("honest game failed the check: %v", err)
+	}
+	if s := r.Status(); !strings.Contains(s.Text, "verified") {
+		t.Fatalf("status %+v", s)
+	}
+}
@@ -0,0 +1,60 @@
+// Package session puts the game a player sees behind one interface, whether
+// the engine runs in this process or on a server. A session only hands out
+// views of the player's own seat and takes their decisions.
+package session
+
+import (
+	"GoGame/internal/game"
+)
+
+// Decision is what a session is waiting for from its player.
+type Decision int
+
+const (
+	DecisionNone Decision = iota
+	DecisionMulligan
+	DecisionAction
+)
+
+// ConnState tells where the game runs and whether it can be reached.
+type ConnState int
+
+const (
+	StateLocal ConnState = iota
+	StateConnected
+	StateReconnecting
+	StateDisconnected
+)
+
+// Status is shown by the connection indicator.
+type Status struct {
+	State ConnState
+	Text  string
+}
+
+// Result is how a game ended. Winner is the winner's name, empty for a tie.
+type Result struct {
+	Winner string
+	Reason string
+}
+
+// Session is a game played from one seat.
+type Session interface {
+	// View returns the game as the player sees it.
+	View() game.View
+	// Waiting tells which decision the game expects from the player now.
+	Waiting() Decision
+	// Act submits an action and waits until it has been applied. For a card
+	// play it returns how the card resolved.
+	Act(a game.Action) (game.PlayResult, error)
+	// Mulligan submits the mulligan choice.
+	Mulligan(indices []int) error
+	// Result reports the outcome once the game is over.
+	Result() (Result, bool)
+	Status() Status
+	// OnUpdate sets a function called, from any goroutine, whenever the
+	// view, the awaited decision or the status changes.
+	OnUpdate(f func())
+	// Close stops the session from calling OnUpdate and releases it.
+	Close() error
+}
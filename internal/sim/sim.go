@@ -0,0 +1,299 @@
+// Package sim plays games between computer controllers without a window.
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"GoGame/internal/ai"
+	"GoGame/internal/analytics"
+	"GoGame/internal/bot"
+	"GoGame/internal/card"
+	"GoGame/internal/game"
+)
+
+// ControllerFactory creates a fresh controller for one game. The seed comes
+// from the game, so a game can be replayed exactly.
+type ControllerFactory func(seed int64) game.Controller
+
+// ControllerNames lists the names accepted by NamedController.
+func ControllerNames() []string {
+	names := []string{"random", "greedy", "mcts", "bot:<command>"}
+	for _, d := range ai.Difficulties {
+		names = append(names, strings.ToLower(d.String()))
+	}
+	return names
+}
+
+// NamedController returns the factory for a controller name: "random",
+// "greedy", "mcts", a difficulty level such as "hard", or "bot:" followed
+// by the command line of an external bot program.
+func NamedController(name string) (ControllerFactory, error) {
+	if command, ok := strings.CutPrefix(name, "bot:"); ok {
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return nil, fmt.Errorf("controller %q has no bot command", name)
+		}
+		return func(seed int64) game.Controller {
+			return bot.New(args[0], args[1:]...)
+		}, nil
+	}
+	switch strings.ToLower(name) {
+	case "random":
+		return func(seed int64) game.Controller {
+			return game.NewRandomController(rand.New(rand.NewSource(seed)))
+		}, nil
+	case "greedy":
+		return func(seed int64) game.Controller {
+			return ai.NewGreedy(ai.DefaultWeights())
+		}, nil
+	case "mcts":
+		return func(seed int64) game.Controller {
+			return ai.NewMCTS(ai.MCTSConfig{Iterations: 200, Seed: seed})
+		}, nil
+	}
+	if d, ok := ai.ParseDifficulty(name); ok {
+		return func(seed int64) game.Controller {
+			return ai.NewController(d, ai.DefaultWeights(), seed)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown controller %q (want one of %s)", name, strings.Join(ControllerNames(), ", "))
+}
+
+// Match describes the games to simulate.
+type Match struct {
+	Decks       [2][]card.Card
+	Rules       game.Rules
+	Controllers [2]ControllerFactory
+}
+
+// Rules returns the default rules with the health tiebreak on, so that
+// fewer simulated games end in a tie and win rates say more.
+func Rules() game.Rules {
+	rules := game.DefaultRules()
+	rules.HealthTiebreak = true
+	return rules
+}
+
+// DefaultMatch uses the default deck and the simulation rules for both seats.
+func DefaultMatch(p1, p2 ControllerFactory) Match {
+	return Match{
+		Decks:       [2][]card.Card{game.InitializeDeck(), game.InitializeDeck()},
+		Rules:       Rules(),
+		Controllers: [2]ControllerFactory{p1, p2},
+	}
+}
+
+// GameRecord is the outcome of one simulated game.
+type GameRecord struct {
+	Seed   int64
+	Winner int // 0 or 1 for the winning seat, -1 for a tie
+	Reason game.EndReason
+	Turns  int
+	Events []game.Event
+	Seats  [2]string // player names, in seat order
+	Hash   uint64    // final game state, lets a replay check it rebuilt the game
+}
+
+// Play runs a single game to the end.
+func Play(m Match, seed int64) (GameRecord, error) {
+	g, err := game.NewGameWithDecks(m.Decks[0], m.Decks[1], m.Rules)
+	if err != nil {
+		return GameRecord{}, err
+	}
+	g.Output = io.Discard
+	g.Seed(seed)
+	controllers := [2]game.Controller{m.Controllers[0](seed), m.Controllers[1](seed + 1)}
+	for _, c := range controllers {
+		if closer, ok := c.(io.Closer); ok {
+			defer closer.Close()
+		}
+	}
+	g.SetController(&g.Player1, controllers[0])
+	g.SetController(&g.Player2, controllers[1])
+	g.DealInitialHands()
+	g.GameLoop()
+
+	result := g.Result()
+	record := GameRecord{
+		Seed:   seed,
+		Winner: -1,
+		Reason: result.Reason,
+		Turns:  g.TurnCount,
+		Events: g.Events,
+		Seats:  [2]string{g.Player1.Name, g.Player2.Name},
+		Hash:   g.Hash(),
+	}
+	switch result.Winner {
+	case &g.Player1:
+		record.Winner = 0
+	case &g.Player2:
+		record.Winner = 1
+	}
+	return record, nil
+}
+
+// RunMany plays n games on up to workers goroutines (0 for one per CPU).
+// Game i uses seed+i, so results do not depend on scheduling. Records are
+// returned in game order.
+func RunMany(m Match, n int, seed int64, workers int) ([]GameRecord, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	records := make([]GameRecord, n)
+	errs := make([]error, n)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				records[i], errs[i] = Play(m, seed+int64(i))
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// Report summarises a batch of games.
+type Report struct {
+	Games      int                `json:"games"`
+	Seats      [2]string          `json:"seats"`
+	Wins       [2]int             `json:"wins"`
+	Ties       int                `json:"ties"`
+	WinRate    [2]float64         `json:"win_rate"`
+	AvgTurns   float64            `json:"avg_turns"`
+	EndReasons map[string]int     `json:"end_reasons"`
+	CardPlays  map[string]int     `json:"card_plays"`
+	PlayRate   map[string]float64 `json:"card_plays_per_game"`
+}
+
+// Summarize builds a report from game records. seats names the
+// controllers in seat order.
+func Summarize(records []GameRecord, seats [2]string) Report {
+	r := Report{
+		Games:      len(records),
+		Seats:      seats,
+		EndReasons: make(map[string]int),
+		CardPlays:  make(map[string]int),
+		PlayRate:   make(map[string]float64),
+	}
+	if len(records) == 0 {
+		return r
+	}
+
+	turns := 0
+	for _, rec := range records {
+		if rec.Winner >= 0 {
+			r.Wins[rec.Winner]++
+		} else {
+			r.Ties++
+		}
+		turns += rec.Turns
+		r.EndReasons[rec.Reason.String()]++
+		for _, e := range rec.Events {
+			if e.Kind == game.EventPlay {
+				for _, name := range e.Cards {
+					r.CardPlays[name]++
+				}
+			}
+		}
+	}
+
+	games := float64(len(records))
+	r.WinRate = [2]float64{float64(r.Wins[0]) / games, float64(r.Wins[1]) / games}
+	r.AvgTurns = float64(turns) / games
+	for name, plays := range r.CardPlays {
+		r.PlayRate[name] = float64(plays) / games
+	}
+	return r
+}
+
+// WriteText prints the report in a human readable form.
+func (r Report) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "Games: %d\n", r.Games)
+	for seat := 0; seat < 2; seat++ {
+		fmt.Fprintf(w, "Seat %d (%s): %d wins (%.1f%%)\n", seat+1, r.Seats[seat], r.Wins[seat], 100*r.WinRate[seat])
+	}
+	fmt.Fprintf(w, "Ties: %d\n", r.Ties)
+	fmt.Fprintf(w, "Average game length: %.1f turns\n", r.AvgTurns)
+
+	fmt.Fprintln(w, "\nGame endings:")
+	for _, reason := range sortedKeys(r.EndReasons) {
+		fmt.Fprintf(w, "  %-12s %d\n", reason, r.EndReasons[reason])
+	}
+
+	fmt.Fprintln(w, "\nCard plays (per game):")
+	names := sortedKeys(r.CardPlays)
+	sort.SliceStable(names, func(i, j int) bool {
+		return r.CardPlays[names[i]] > r.CardPlays[names[j]]
+	})
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %6d (%.2f)\n", name, r.CardPlays[name], r.PlayRate[name])
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// AnalyticsGame converts the record for the analytics package.
+func (r GameRecord) AnalyticsGame() analytics.Game {
+	g := analytics.Game{Players: r.Seats, Events: r.Events}
+	if r.Winner >= 0 {
+		g.Winner = r.Seats[r.Winner]
+	}
+	return g
+}
+
+// WriteRecords stores game records as JSON lines, one game per line.
+func WriteRecords(w io.Writer, records []GameRecord) error {
+	enc := json.NewEncoder(w)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadRecords loads game records written by WriteRecords.
+func ReadRecords(r io.Reader) ([]GameRecord, error) {
+	var records []GameRecord
+	dec := json.NewDecoder(r)
+	for {
+		var rec GameRecord
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read records: %w", err)
+		}
+		records = append(records, rec)
+	}
+}
@@ -0,0 +1,72 @@
+package sim
+
+import (
+	"testing"
+
+	"GoGame/internal/game"
+)
+
+func match(t *testing.T, p1, p2 string) Match {
+	t.Helper()
+	c1, err := NamedController(p1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := NamedController(p2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return DefaultMatch(c1, c2)
+}
+
+func TestRunManyIsDeterministic(t *testing.T) {
+	m := match(t, "random", "greedy")
+	serial, err := RunMany(m, 12, 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := RunMany(m, 12, 100, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range serial {
+		a, b := serial[i], parallel[i]
+		if a.Seed != 100+int64(i) || a.Seed != b.Seed {
+			t.Fatalf("game %d: seeds %d and %d", i, a.Seed, b.Seed)
+		}
+		if a.Winner != b.Winner || a.Reason != b.Reason || a.Turns != b.Turns || a.Hash != b.Hash {
+			t.Fatalf("game %d differs between runs: %+v and %+v", i, a, b)
+		}
+	}
+	if serial[0].Hash == serial[1].Hash {
+		t.Fatal("games with different seeds ended the same")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	records := []GameRecord{
+		{Winner: 0, Reason: game.EndHealth, Turns: 10, Events: []game.Event{{Kind: game.EventPlay, Cards: []string{"Fireball"}}}},
+		{Winner: 1, Reason: game.EndTurnLimit, Turns: 20},
+		{Winner: -1, Reason: game.EndTurnLimit, Turns: 30, Events: []game.Event{
+			{Kind: game.EventPlay, Cards: []string{"Fireball"}},
+			{Kind: game.EventDraw, Cards: []string{"Heal"}},
+		}},
+	}
+	r := Summarize(records, [2]string{"a", "b"})
+	if r.Wins != [2]int{1, 1} || r.Ties != 1 || r.AvgTurns != 20 {
+		t.Fatalf("wins %v, ties %d, average %v", r.Wins, r.Ties, r.AvgTurns)
+	}
+	if r.EndReasons[game.EndTurnLimit.String()] != 2 || r.CardPlays["Fireball"] != 2 || r.CardPlays["Heal"] != 0 {
+		t.Fatalf("endings %v, plays %v", r.EndReasons, r.CardPlays)
+	}
+}
+
+func TestSimulationRulesBreakTies(t *testing.T) {
+	if game.DefaultRules().HealthTiebreak {
+		t.Fatal("the default rules break ties on health")
+	}
+	if !Rules().HealthTiebreak || !DefaultMatch(nil, nil).Rules.HealthTiebreak {
+		t.Fatal("simulations do not break ties on health")
+	}
+}
@@ -3,119 +3,527 @@ package ui
 import (
 	"fmt"
 	"image/color"
-
+	"sync"
+	"time"
+
+	"GoGame/internal/ai"
+	"GoGame/internal/bot"
+	"GoGame/internal/card"
+	"GoGame/internal/deck"
+	"GoGame/internal/discovery"
 	"GoGame/internal/game"
 	"GoGame/internal/player"
+	"GoGame/internal/session"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 )
 
 var endTurnButton *widget.Button
 var newGameButton *widget.Button
+var mulliganShown bool
+var resultShown bool
+var cheatShown bool
+var aiWeights = ai.DefaultWeights()
+var opponentBot *bot.Process // plays the computer seat instead of the AI if set
+
+// current is the session the board shows: the local game or a remote one.
+var current session.Session
+
+// refreshBoard redraws the board from the current session.
+var refreshBoard func()
+
+// boardWindow is the window the board is shown in.
+var boardWindow fyne.Window
+
+// fyne 2.5 cannot run code on its main goroutine for us, so the board has a
+// goroutine of its own: sessions, network calls and taps queue their updates
+// with onUI and runUI applies them one at a time, in order. The board's
+// package state is only touched there.
+var (
+	uiMu    sync.Mutex
+	uiQueue []func()
+	uiWake  = make(chan struct{}, 1)
+)
+
+// onUI queues f to run on the board's goroutine. It never blocks, so it may
+// be called from the board's goroutine too.
+func onUI(f func()) {
+	uiMu.Lock()
+	uiQueue = append(uiQueue, f)
+	uiMu.Unlock()
+	select {
+	case uiWake <- struct{}{}:
+	default:
+	}
+}
+
+func runUI() {
+	for range uiWake {
+		for {
+			uiMu.Lock()
+			if len(uiQueue) == 0 {
+				uiMu.Unlock()
+				break
+			}
+			f := uiQueue[0]
+			uiQueue = uiQueue[1:]
+			uiMu.Unlock()
+			f()
+		}
+	}
+}
+
+// difficultyPreference is the preferences key of the last chosen difficulty.
+const difficultyPreference = "difficulty"
 
+// Preferences keys of the last server and player name used to join a game.
+const (
+	serverPreference = "server"
+	namePreference   = "name"
+)
+
+// SetAIWeights sets the evaluation weights used by computer opponents.
+func SetAIWeights(w ai.Weights) {
+	aiWeights = w
+}
+
+// SetBot makes an external bot program play the computer seat instead of
+// the built-in AI. The program is kept running from game to game.
+func SetBot(p *bot.Process) {
+	opponentBot = p
+}
+
+// SetupUI builds the board for the local game g. The board only talks to a
+// session.Session, so it can be switched to a game on a server with the
+// "Join game" button.
 func SetupUI(g *game.Game) {
-	g.ScoreLabel = widget.NewLabel(fmt.Sprintf("Score - %s: %d, %s: %d", g.Player1.Name, g.Player1.Score, g.Player2.Name, g.Player2.Score))
+	window := g.GetWindow()
+	boardWindow = window
+	go runUI()
+	scoreLabel := widget.NewLabel("")
 	phaseLabel := widget.NewLabel("Current Phase: Draw")
+	statusLight := canvas.NewCircle(statusColor(session.StateLocal))
+	statusLight.Resize(fyne.NewSize(12, 12))
+	statusLabel := widget.NewLabel("")
+	status := container.NewHBox(container.NewGridWrap(fyne.NewSize(12, 12), statusLight), statusLabel)
 
-	player1Field := createPlayerField(g, &g.Player1, true)
-	player2Field := createPlayerField(g, &g.Player2, false)
+	player1Field := createPlayerField(true)
+	player2Field := createPlayerField(false)
 
 	endTurnButton = widget.NewButton("End Turn", func() {
-		g.EndTurn <- true
+		onUI(func() {
+			s := current
+			go s.Act(game.Action{Kind: game.ActionEndTurn})
+		})
 	})
 	endTurnButton.Disable()
 
+	applyDifficulty(g, savedDifficulty())
+
 	newGameButton = widget.NewButton("New Game", func() {
-		startNewGame(g)
+		showNewGameDialog(g)
+	})
+	joinButton := widget.NewButton("Join game", func() {
+		showJoinDialog(window)
+	})
+	lanButton := widget.NewButton("Join LAN game", func() {
+		showLANDialog(window)
+	})
+
+	copyDeckButton := widget.NewButton("Copy deck code", func() {
+		copyDeckCode(g)
+	})
+	importDeckButton := widget.NewButton("Import deck code", func() {
+		importDeckCode(g)
 	})
 
 	gameBoard := container.NewVBox(
 		player2Field,
 		widget.NewSeparator(),
 		player1Field,
-		container.NewHBox(endTurnButton, newGameButton),
+		container.NewHBox(endTurnButton, newGameButton, joinButton, lanButton, copyDeckButton, importDeckButton),
 	)
 
-	content := container.NewBorder(container.NewVBox(g.ScoreLabel, phaseLabel), nil, nil, nil, gameBoard)
+	content := container.NewBorder(container.NewVBox(container.NewBorder(nil, nil, nil, status, scoreLabel), phaseLabel), nil, nil, nil, gameBoard)
+
+	window.SetContent(content)
+	window.Resize(fyne.NewSize(1920, 1080))
+
+	refreshBoard = func() {
+		v := current.View()
+		scoreLabel.SetText(fmt.Sprintf("Score - %s: %d, %s: %d", v.Self.Name, v.Self.Score, v.Opponent.Name, v.Opponent.Score))
+		updatePhaseLabel(v, phaseLabel)
+		updateStatus(current.Status(), statusLight, statusLabel)
+		updateEndTurnButton()
+		updatePlayerField(v.Self, true, player1Field)
+		updatePlayerField(v.Opponent, false, player2Field)
+		window.Canvas().Refresh(content)
+
+		// a rejected mulligan is asked again, so the dialog is shown once
+		// per request rather than once per game
+		if current.Waiting() != session.DecisionMulligan {
+			mulliganShown = false
+		} else if !mulliganShown {
+			mulliganShown = true
+			showMulliganDialog(v, window)
+		}
+		if result, over := current.Result(); over && !resultShown {
+			resultShown = true
+			showGameResult(v, result, window)
+		}
+		if remote, ok := current.(*session.Remote); ok && !cheatShown {
+			if checked, err := remote.Fairness(); checked && err != nil {
+				cheatShown = true
+				dialog.ShowError(err, window)
+			}
+		}
+	}
+	useSession(session.NewLocal(g))
+}
+
+// useSession shows s on the board in place of the current session.
+func useSession(s session.Session) {
+	if current != nil {
+		current.Close()
+	}
+	current = s
+	mulliganShown = false
+	resultShown = false
+	cheatShown = false
+	s.OnUpdate(func() { onUI(refreshBoard) })
+	refreshBoard()
+}
 
-	g.GetWindow().SetContent(content)
-	g.GetWindow().Resize(fyne.NewSize(1920, 1080))
+func showNewGameDialog(g *game.Game) {
+	names := make([]string, len(ai.Difficulties))
+	for i, d := range ai.Difficulties {
+		names[i] = d.String()
+	}
+	difficulty := widget.NewSelect(names, nil)
+	difficulty.SetSelected(savedDifficulty().String())
 
-	// Set up the UIUpdate function
-	g.UIUpdate = func() {
-		g.GetWindow().Canvas().Refresh(content)
-		updatePhaseLabel(g, phaseLabel)
-		updateEndTurnButton(g)
-		updatePlayerFields(g, player1Field, player2Field)
+	form := []*widget.FormItem{
+		widget.NewFormItem("Difficulty", difficulty),
 	}
+	dialog.ShowForm("New Game", "Start", "Cancel", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		d, _ := ai.ParseDifficulty(difficulty.Selected)
+		fyne.CurrentApp().Preferences().SetString(difficultyPreference, d.String())
+		applyDifficulty(g, d)
+		startNewGame(g)
+	}, g.GetWindow())
+}
+
+func savedDifficulty() ai.Difficulty {
+	name := fyne.CurrentApp().Preferences().StringWithFallback(difficultyPreference, ai.Normal.String())
+	d, _ := ai.ParseDifficulty(name)
+	return d
+}
+
+func applyDifficulty(g *game.Game, d ai.Difficulty) {
+	var opponent game.Controller = ai.NewController(d, aiWeights, time.Now().UnixNano())
+	if opponentBot != nil {
+		opponent = opponentBot
+	}
+	g.SetController(&g.Player2, game.WithDelay(opponent, time.Second))
 }
 
 func startNewGame(g *game.Game) {
-	g.Reset()
-	g.UIUpdate()
-	go g.GameLoop()
+	onUI(func() {
+		g.Reset()
+		useSession(session.NewLocal(g))
+		go g.GameLoop()
+	})
+}
+
+// showJoinDialog asks for a server address and joins the next game there.
+func showJoinDialog(window fyne.Window) {
+	prefs := fyne.CurrentApp().Preferences()
+	addr := widget.NewEntry()
+	addr.SetPlaceHolder("host:port")
+	addr.SetText(prefs.StringWithFallback(serverPreference, "localhost:7777"))
+	name := widget.NewEntry()
+	name.SetText(prefs.StringWithFallback(namePreference, "Player"))
+
+	form := []*widget.FormItem{
+		widget.NewFormItem("Server", addr),
+		widget.NewFormItem("Name", name),
+	}
+	dialog.ShowForm("Join game", "Join", "Cancel", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		prefs.SetString(serverPreference, addr.Text)
+		prefs.SetString(namePreference, name.Text)
+		go joinGame(addr.Text, name.Text, window)
+	}, window)
+}
+
+// showLANDialog lists the games announced on the local network and joins
+// the chosen one.
+func showLANDialog(window fyne.Window) {
+	prefs := fyne.CurrentApp().Preferences()
+	name := widget.NewEntry()
+	name.SetText(prefs.StringWithFallback(namePreference, "Player"))
+	status := widget.NewLabel("")
+
+	// the list reads games from fyne's goroutine and the search writes it
+	// from its own, so both go through mu
+	var mu sync.Mutex
+	var games []discovery.Game
+	selected := -1
+	list := widget.NewList(
+		func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(games)
+		},
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			mu.Lock()
+			if i >= len(games) {
+				mu.Unlock()
+				return
+			}
+			g := games[i]
+			mu.Unlock()
+			o.(*widget.Label).SetText(fmt.Sprintf("%s - %s rules, %d open seats (%s)", g.Name, g.Ruleset, g.Seats, g.Addr))
+		},
+	)
+	list.OnSelected = func(i widget.ListItemID) {
+		mu.Lock()
+		selected = i
+		mu.Unlock()
+	}
+
+	var search *widget.Button
+	search = widget.NewButton("Search again", func() {
+		search.Disable()
+		status.SetText("Searching the local network...")
+		go func() {
+			found, err := discovery.Scan(2 * time.Second)
+			switch {
+			case err != nil:
+				status.SetText(fmt.Sprintf("Could not search: %v", err))
+			case len(found) == 0:
+				status.SetText("No games found")
+			default:
+				status.SetText(fmt.Sprintf("%d games found", len(found)))
+			}
+			list.UnselectAll()
+			mu.Lock()
+			games, selected = found, -1
+			mu.Unlock()
+			list.Refresh()
+			search.Enable()
+		}()
+	})
+
+	top := container.NewVBox(widget.NewForm(widget.NewFormItem("Name", name)), container.NewBorder(nil, nil, nil, search, status))
+	d := dialog.NewCustomConfirm("Join LAN game", "Join", "Cancel", container.NewBorder(top, nil, nil, nil, list), func(ok bool) {
+		if !ok {
+			return
+		}
+		prefs.SetString(namePreference, name.Text)
+		mu.Lock()
+		defer mu.Unlock()
+		if selected >= 0 && selected < len(games) {
+			go joinGame(games[selected].Addr, name.Text, window)
+		}
+	}, window)
+	d.Resize(fyne.NewSize(560, 400))
+	d.Show()
+	search.OnTapped()
+}
+
+// joinGame connects from a goroutine of its own and hands the session to
+// the board's goroutine.
+func joinGame(addr, name string, window fyne.Window) {
+	remote, err := session.Dial(addr, name)
+	onUI(func() {
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("could not connect to %s: %w", addr, err), window)
+			return
+		}
+		useSession(remote)
+	})
+}
+
+func copyDeckCode(g *game.Game) {
+	list, err := deck.FromCards(g.Player1.Name+"'s deck", g.DeckList(&g.Player1))
+	if err != nil {
+		dialog.ShowError(err, g.GetWindow())
+		return
+	}
+	g.GetWindow().Clipboard().SetContent(deck.Encode(list))
+	dialog.ShowInformation("Deck code", "Deck code copied to the clipboard.", g.GetWindow())
+}
+
+func importDeckCode(g *game.Game) {
+	entry := widget.NewEntry()
+	entry.SetText(g.GetWindow().Clipboard().Content())
+	dialog.ShowCustomConfirm("Import deck code", "Import", "Cancel", entry, func(ok bool) {
+		if !ok {
+			return
+		}
+		list, err := deck.Decode(entry.Text)
+		if err != nil {
+			dialog.ShowError(err, g.GetWindow())
+			return
+		}
+		cards, err := list.Build(game.FindCardByID)
+		if err != nil {
+			dialog.ShowError(err, g.GetWindow())
+			return
+		}
+		if err := g.SetDeckList(&g.Player1, cards); err != nil {
+			dialog.ShowError(err, g.GetWindow())
+			return
+		}
+		dialog.ShowInformation("Deck imported",
+			fmt.Sprintf("%q (%d cards) will be used from the next local game.", list.Name, len(cards)), g.GetWindow())
+	}, g.GetWindow())
 }
 
-func updatePhaseLabel(g *game.Game, label *widget.Label) {
+func showMulliganDialog(v game.View, window fyne.Window) {
+	hand := v.Self.Hand
+	checks := make([]*widget.Check, len(hand))
+	content := container.NewVBox()
+	if v.Rules.Mulligan == game.MulliganFull {
+		content.Add(widget.NewLabel("Redraw your whole opening hand?"))
+		for _, c := range hand {
+			content.Add(widget.NewLabel(c.GetInfo()))
+		}
+	} else {
+		content.Add(widget.NewLabel("Select the cards to shuffle back and redraw:"))
+		for i, c := range hand {
+			checks[i] = widget.NewCheck(c.GetInfo(), nil)
+			content.Add(checks[i])
+		}
+	}
+
+	s := current
+	dialog.ShowCustomConfirm("Mulligan", "Redraw", "Keep hand", content, func(redraw bool) {
+		var indices []int
+		if redraw {
+			for i := range hand {
+				if checks[i] == nil || checks[i].Checked {
+					indices = append(indices, i)
+				}
+			}
+		}
+		onUI(func() {
+			if current == s {
+				mulliganShown = false
+			}
+		})
+		go s.Mulligan(indices)
+	}, window)
+}
+
+func updatePhaseLabel(v game.View, label *widget.Label) {
 	var phase string
-	switch g.CurrentPhase {
+	switch v.Phase {
 	case game.DrawPhase:
 		phase = "Draw"
 	case game.PlayPhase:
 		phase = "Play"
 	case game.EndPhase:
 		phase = "End"
+	case game.MulliganPhase:
+		phase = "Mulligan"
 	}
 	label.SetText(fmt.Sprintf("Current Phase: %s", phase))
 }
 
-func updateEndTurnButton(g *game.Game) {
-	if g.CurrentPlayer == &g.Player1 && g.CurrentPhase == game.PlayPhase {
+func updateStatus(status session.Status, light *canvas.Circle, label *widget.Label) {
+	light.FillColor = statusColor(status.State)
+	light.Refresh()
+	label.SetText(status.Text)
+}
+
+func statusColor(state session.ConnState) color.Color {
+	switch state {
+	case session.StateConnected:
+		return color.NRGBA{R: 40, G: 180, B: 60, A: 255}
+	case session.StateReconnecting:
+		return color.NRGBA{R: 230, G: 160, B: 30, A: 255}
+	case session.StateDisconnected:
+		return color.NRGBA{R: 200, G: 40, B: 40, A: 255}
+	default:
+		return color.NRGBA{R: 120, G: 120, B: 120, A: 255}
+	}
+}
+
+func updateEndTurnButton() {
+	if current.Waiting() == session.DecisionAction {
 		endTurnButton.Enable()
 	} else {
 		endTurnButton.Disable()
 	}
 }
 
-func updatePlayerFields(g *game.Game, player1Field, player2Field *fyne.Container) {
-	updatePlayerField(g, &g.Player1, player1Field)
-	updatePlayerField(g, &g.Player2, player2Field)
-}
+func updatePlayerField(state game.PlayerState, isSelf bool, field *fyne.Container) {
+	// Update deck and discard counters
+	field.Objects[0].(*widget.Button).SetText(fmt.Sprintf("Deck (%d)", state.DeckSize))
+	field.Objects[1].(*widget.Button).SetText(fmt.Sprintf("Discard (%d)", len(state.Discard)))
 
-func updatePlayerField(g *game.Game, player *player.Player, field *fyne.Container) {
 	// Update player card
-	playerCard := field.Objects[2].(*fyne.Container).Objects[1].(*fyne.Container)
-	updatePlayerCard(player, playerCard)
+	playerCard := field.Objects[3].(*fyne.Container).Objects[1].(*fyne.Container)
+	updatePlayerCard(state, playerCard)
+
+	// Update units on the field
+	board := field.Objects[3].(*fyne.Container)
+	left, right := boardSlots(state.Board)
+	updateCardSlots(left, board.Objects[0].(*fyne.Container))
+	updateCardSlots(right, board.Objects[2].(*fyne.Container))
 
 	// Update hand
-	handCards := field.Objects[1].(*fyne.Container)
-	updateHandCards(g, player, handCards)
+	handCards := field.Objects[2].(*fyne.Container)
+	updateHandCards(state, isSelf, handCards)
 }
 
-func createPlayerField(g *game.Game, player *player.Player, isBottom bool) *fyne.Container {
-	playerCard := createPlayerCard(player)
-	cardSpaces := createCardSpaces()
-	
-	var handCards *fyne.Container
-	var deck, discardPile *widget.Button
-	
-	if isBottom {
-		handCards = createHandCards(g, player)
-		deck = widget.NewButton(fmt.Sprintf("Deck (%d)", len(g.Deck)), func() {
-			showDeckInfo(g)
-		})
-		discardPile = widget.NewButton(fmt.Sprintf("Discard (%d)", len(g.DiscardPile)), func() {
-			showDiscardPileInfo(g)
-		})
-	} else {
-		handCards = container.NewHBox() // Empty container for opponent's hand
-		deck = widget.NewButton(fmt.Sprintf("Deck (%d)", len(g.Deck)), nil) // Placeholder for opponent's deck
-		discardPile = widget.NewButton(fmt.Sprintf("Discard (%d)", len(g.DiscardPile)), nil) // Placeholder for opponent's discard pile
+// boardSlots lays units out the way the engine places them: the left slots
+// first, then the right ones.
+func boardSlots(units []card.Card) (left, right [3]game.CardSlot) {
+	for i := range units {
+		slot := game.CardSlot{Card: &units[i], IsOccupied: true}
+		if i < 3 {
+			left[i] = slot
+		} else if i < 6 {
+			right[i-3] = slot
+		}
 	}
+	return left, right
+}
+
+// stateOf returns the current view of one side of the board.
+func stateOf(isSelf bool) game.PlayerState {
+	v := current.View()
+	if isSelf {
+		return v.Self
+	}
+	return v.Opponent
+}
+
+func createPlayerField(isSelf bool) *fyne.Container {
+	playerCard := createPlayerCard(isSelf)
+	cardSpaces := createCardSpaces()
+	handCards := container.NewHBox()
+
+	deck := widget.NewButton("Deck (0)", func() {
+		onUI(func() { showDeckInfo(stateOf(isSelf), boardWindow) })
+	})
+	discardPile := widget.NewButton("Discard (0)", func() {
+		onUI(func() { showDiscardPileInfo(stateOf(isSelf), boardWindow) })
+	})
 
 	field := container.New(layout.NewBorderLayout(nil, handCards, deck, discardPile),
 		deck, discardPile, handCards,
@@ -124,38 +532,60 @@ func createPlayerField(g *game.Game, player *player.Player, isBottom bool) *fyne
 	return field
 }
 
-func createPlayerCard(player *player.Player) *fyne.Container {
-	nameLabel := widget.NewLabel(player.Name)
-	healthLabel := widget.NewLabel(fmt.Sprintf("Health: %d/%d", player.Health, player.MaxHealth))
-	manaLabel := widget.NewLabel(fmt.Sprintf("Mana: %d/%d", player.Mana, player.MaxMana))
-	armorLabel := widget.NewLabel(fmt.Sprintf("Armor: %d", player.Armor))
-	ringLabel := widget.NewLabel(fmt.Sprintf("Ring: %s", getItemName(player.Ring)))
-	necklaceLabel := widget.NewLabel(fmt.Sprintf("Necklace: %s", getItemName(player.Necklace)))
-	weaponLabel := widget.NewLabel(fmt.Sprintf("Weapon: %s", getItemName(player.Weapon)))
-	
+func createPlayerCard(isSelf bool) *fyne.Container {
 	statsButton := widget.NewButton("View Stats", func() {
-		showPlayerStats(player)
+		onUI(func() { showPlayerStats(stateOf(isSelf), boardWindow) })
 	})
 
 	return container.NewVBox(
-		nameLabel,
-		healthLabel,
-		manaLabel,
-		armorLabel,
-		ringLabel,
-		necklaceLabel,
-		weaponLabel,
+		widget.NewLabel(""), // name
+		widget.NewLabel(""), // health
+		widget.NewLabel(""), // mana
+		widget.NewLabel(""), // armor
+		widget.NewLabel(""), // ring
+		widget.NewLabel(""), // necklace
+		widget.NewLabel(""), // weapon
 		statsButton,
 	)
 }
 
-func updatePlayerCard(player *player.Player, card *fyne.Container) {
-	card.Objects[1].(*widget.Label).SetText(fmt.Sprintf("Health: %d/%d", player.Health, player.MaxHealth))
-	card.Objects[2].(*widget.Label).SetText(fmt.Sprintf("Mana: %d/%d", player.Mana, player.MaxMana))
-	card.Objects[3].(*widget.Label).SetText(fmt.Sprintf("Armor: %d", player.Armor))
-	card.Objects[4].(*widget.Label).SetText(fmt.Sprintf("Ring: %s", getItemName(player.Ring)))
-	card.Objects[5].(*widget.Label).SetText(fmt.Sprintf("Necklace: %s", getItemName(player.Necklace)))
-	card.Objects[6].(*widget.Label).SetText(fmt.Sprintf("Weapon: %s", getItemName(player.Weapon)))
+func updatePlayerCard(state game.PlayerState, card *fyne.Container) {
+	card.Objects[0].(*widget.Label).SetText(state.Name)
+	card.Objects[1].(*widget.Label).SetText(fmt.Sprintf("Health: %d/%d", state.Health, state.MaxHealth))
+	card.Objects[2].(*widget.Label).SetText(fmt.Sprintf("Mana: %d/%d", state.Mana, state.MaxMana))
+	card.Objects[3].(*widget.Label).SetText(fmt.Sprintf("Armor: %d", state.Armor))
+	card.Objects[4].(*widget.Label).SetText(fmt.Sprintf("Ring: %s", getItemName(state.Ring)))
+	card.Objects[5].(*widget.Label).SetText(fmt.Sprintf("Necklace: %s", getItemName(state.Necklace)))
+	card.Objects[6].(*widget.Label).SetText(fmt.Sprintf("Weapon: %s", getItemName(state.Weapon)))
+}
+
+func describeModifier(m player.Modifier) string {
+	value := fmt.Sprintf("%+d", m.Value)
+	if m.Kind == player.PercentModifier {
+		value += "%"
+	}
+	text := fmt.Sprintf("%s: %s %s", m.Source, value, statName(m.Stat))
+	if m.Duration > 0 {
+		text += fmt.Sprintf(" (%d turns)", m.Duration)
+	}
+	return text
+}
+
+func statName(stat player.Stat) string {
+	switch stat {
+	case player.StatMaxHealth:
+		return "max health"
+	case player.StatMaxMana:
+		return "max mana"
+	case player.StatArmor:
+		return "armor"
+	case player.StatDamage:
+		return "damage"
+	case player.StatHealing:
+		return "healing"
+	default:
+		return "unknown"
+	}
 }
 
 func getItemName(item *player.Item) string {
@@ -165,6 +595,13 @@ func getItemName(item *player.Item) string {
 	return item.Name
 }
 
+func itemBonus(item *player.Item) int {
+	if item == nil {
+		return 0
+	}
+	return item.Bonus
+}
+
 func createCardSpaces() [2]*fyne.Container {
 	leftSpace := container.NewVBox()
 	rightSpace := container.NewVBox()
@@ -184,111 +621,116 @@ func createCardSlot() *fyne.Container {
 	return container.NewMax(slot, widget.NewLabel(""))
 }
 
-func createHandCards(g *game.Game, player *player.Player) *fyne.Container {
-	handCards := container.NewHBox()
-
-	for i, card := range player.Hand {
-		cardButton := widget.NewButton(card.GetInfo(), func(i int) func() {
-			return func() {
-				playCard(g, player, i)
-			}
-		}(i))
-		handCards.Add(cardButton)
+func updateCardSlots(slots [3]game.CardSlot, space *fyne.Container) {
+	for i, slot := range slots {
+		label := space.Objects[i].(*fyne.Container).Objects[1].(*widget.Label)
+		if slot.IsOccupied {
+			label.SetText(fmt.Sprintf("%s\nPower: %d", slot.Card.Name, slot.Card.Power))
+		} else {
+			label.SetText("")
+		}
 	}
-
-	return handCards
 }
 
-func updateHandCards(g *game.Game, player *player.Player, handCards *fyne.Container) {
+// updateHandCards shows the player's own hand as buttons and only the size
+// of the opponent's hand.
+func updateHandCards(state game.PlayerState, isSelf bool, handCards *fyne.Container) {
 	handCards.RemoveAll()
-	for i, card := range player.Hand {
+	if !isSelf {
+		handCards.Add(widget.NewLabel(fmt.Sprintf("Hand: %d cards", state.HandSize)))
+		return
+	}
+	for i, card := range state.Hand {
 		cardButton := widget.NewButton(card.GetInfo(), func(i int) func() {
 			return func() {
-				playCard(g, player, i)
+				playCard(i)
 			}
 		}(i))
 		handCards.Add(cardButton)
 	}
 }
 
-func playCard(g *game.Game, player *player.Player, cardIndex int) {
-	if g.CurrentPlayer == player && g.CurrentPhase == game.PlayPhase {
-		g.PlayCard(player, cardIndex)
-		showRoundResult(g)
-
-		// Check if the game is over
-		if g.CheckGameOver() {
-			showGameResult(g)
+func playCard(cardIndex int) {
+	onUI(func() {
+		if current.Waiting() != session.DecisionAction {
+			return
 		}
 
-		// Update UI
-		g.UIUpdate()
-	}
+		s := current
+		go func() {
+			play, err := s.Act(game.Action{Kind: game.ActionPlayCard, CardIndex: cardIndex})
+			if err != nil {
+				return
+			}
+			name := s.View().Self.Name
+			onUI(func() { showRoundResult(name, play, boardWindow) })
+		}()
+	})
 }
 
-func showRoundResult(g *game.Game) {
-	lastPlay := g.LastPlay
+func showRoundResult(name string, lastPlay game.PlayResult, window fyne.Window) {
 	message := fmt.Sprintf(
 		"%s played %s\n%s",
-		g.CurrentPlayer.Name, lastPlay.PlayerCard.GetInfo(),
+		name, lastPlay.PlayerCard.GetInfo(),
 		lastPlay.Message,
 	)
+	for _, calc := range lastPlay.Calculations {
+		message += "\n\n" + calc.Breakdown()
+	}
 
-	dialog := widget.NewLabel(message)
-	popUp := widget.NewPopUp(dialog, g.GetWindow().Canvas())
-	popUp.Show()
+	showPopUp(message, window)
 }
 
-func showGameResult(g *game.Game) {
-	var winner string
-	if g.Player1.Health <= 0 || len(g.Player1.Hand) == 0 {
-		winner = g.Player2.Name
-	} else if g.Player2.Health <= 0 || len(g.Player2.Hand) == 0 {
-		winner = g.Player1.Name
-	} else if g.Player1.Score > g.Player2.Score {
-		winner = g.Player1.Name
-	} else if g.Player2.Score > g.Player1.Score {
-		winner = g.Player2.Name
-	} else {
-		winner = "It's a tie!"
+func showGameResult(v game.View, result session.Result, window fyne.Window) {
+	winner := "It's a tie!"
+	if result.Winner != "" {
+		winner = result.Winner
 	}
 
-	message := fmt.Sprintf("Game Over!\n%s wins!\n\nFinal Score:\n%s: %d (Health: %d)\n%s: %d (Health: %d)", 
-		winner, g.Player1.Name, g.Player1.Score, g.Player1.Health, g.Player2.Name, g.Player2.Score, g.Player2.Health)
+	message := fmt.Sprintf("Game Over (%s)!\n%s wins!\n\nFinal Score:\n%s: %d (Health: %d)\n%s: %d (Health: %d)",
+		result.Reason, winner, v.Self.Name, v.Self.Score, v.Self.Health, v.Opponent.Name, v.Opponent.Score, v.Opponent.Health)
 
 	dialog := widget.NewLabel(message)
-	popUp := widget.NewPopUp(dialog, g.GetWindow().Canvas())
+	popUp := widget.NewPopUp(dialog, window.Canvas())
 	popUp.Show()
 }
 
-func showDeckInfo(g *game.Game) {
-	message := fmt.Sprintf("Remaining cards in deck: %d", len(g.Deck))
-	dialog := widget.NewLabel(message)
-	popUp := widget.NewPopUp(dialog, g.GetWindow().Canvas())
-	popUp.Show()
+func showDeckInfo(state game.PlayerState, window fyne.Window) {
+	showPopUp(fmt.Sprintf("Remaining cards in %s's deck: %d", state.Name, state.DeckSize), window)
 }
 
-func showDiscardPileInfo(g *game.Game) {
-	message := fmt.Sprintf("Cards in discard pile: %d\n\n", len(g.DiscardPile))
-	for _, card := range g.DiscardPile {
+func showDiscardPileInfo(state game.PlayerState, window fyne.Window) {
+	message := fmt.Sprintf("Cards in %s's discard pile: %d\n\n", state.Name, len(state.Discard))
+	for _, card := range state.Discard {
 		message += card.GetInfo() + "\n\n"
 	}
-	dialog := widget.NewLabel(message)
-	popUp := widget.NewPopUp(dialog, g.GetWindow().Canvas())
-	popUp.Show()
+	showPopUp(message, window)
 }
 
-func showPlayerStats(player *player.Player) {
-	message := fmt.Sprintf("Player: %s\n\n", player.Name)
-	message += fmt.Sprintf("Health: %d/%d\n", player.Health, player.MaxHealth)
-	message += fmt.Sprintf("Mana: %d/%d\n", player.Mana, player.MaxMana)
-	message += fmt.Sprintf("Armor: %d\n", player.Armor)
-	message += fmt.Sprintf("Ring: %s\n", getItemName(player.Ring))
-	message += fmt.Sprintf("Necklace: %s\n", getItemName(player.Necklace))
-	message += fmt.Sprintf("Weapon: %s\n", getItemName(player.Weapon))
-	message += fmt.Sprintf("Total Bonus: %d", player.GetTotalBonus())
+func showPlayerStats(state game.PlayerState, window fyne.Window) {
+	message := fmt.Sprintf("Player: %s\n\n", state.Name)
+	message += fmt.Sprintf("Health: %d/%d\n", state.Health, state.MaxHealth)
+	message += fmt.Sprintf("Mana: %d/%d\n", state.Mana, state.MaxMana)
+	message += fmt.Sprintf("Armor: %d\n", state.Armor)
+	message += fmt.Sprintf("Ring: %s\n", getItemName(state.Ring))
+	message += fmt.Sprintf("Necklace: %s\n", getItemName(state.Necklace))
+	message += fmt.Sprintf("Weapon: %s\n", getItemName(state.Weapon))
+	message += fmt.Sprintf("Item bonus total: %d\n\n", itemBonus(state.Ring)+itemBonus(state.Necklace)+itemBonus(state.Weapon))
+	message += fmt.Sprintf("Damage bonus: %+d\n", state.DamageBonus)
+	message += fmt.Sprintf("Healing bonus: %+d\n", state.HealingBonus)
+	message += fmt.Sprintf("Max mana bonus: %+d", state.ManaBonus)
+	if len(state.Modifiers) > 0 {
+		message += "\n\nModifiers:"
+		for _, m := range state.Modifiers {
+			message += "\n" + describeModifier(m)
+		}
+	}
+
+	showPopUp(message, window)
+}
 
+func showPopUp(message string, window fyne.Window) {
 	dialog := widget.NewLabel(message)
-	popUp := widget.NewPopUp(dialog, fyne.CurrentApp().Driver().AllWindows()[0].Canvas())
+	popUp := widget.NewPopUp(dialog, window.Canvas())
 	popUp.Show()
-}
\ No newline at end of file
+}